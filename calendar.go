@@ -0,0 +1,66 @@
+package gotimer
+
+import "time"
+
+// Calendar - タスクを実行しない日を判定する
+type Calendar interface {
+	IsHoliday(date time.Time) bool
+}
+
+// NewStaticCalendar - datesの日付を休日とするカレンダーを返す
+func NewStaticCalendar(dates ...time.Time) *StaticCalendar {
+	c := &StaticCalendar{dates: map[date]struct{}{}}
+	for _, d := range dates {
+		c.dates[dateOf(d)] = struct{}{}
+	}
+	return c
+}
+
+// StaticCalendar - 決められた日付の一覧を休日とするカレンダー
+type StaticCalendar struct {
+	dates map[date]struct{}
+}
+
+// IsHoliday - 一覧にある日付なら休日
+func (c *StaticCalendar) IsHoliday(d time.Time) bool {
+	_, ok := c.dates[dateOf(d)]
+	return ok
+}
+
+// WeekendCalendar - 土曜日と日曜日を休日とするカレンダー
+type WeekendCalendar struct{}
+
+// IsHoliday - 土曜日か日曜日なら休日
+func (WeekendCalendar) IsHoliday(d time.Time) bool {
+	return d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+}
+
+// NewCompositeCalendar - 複数のカレンダーを組み合わせたカレンダーを返す
+func NewCompositeCalendar(calendars ...Calendar) CompositeCalendar {
+	return CompositeCalendar(calendars)
+}
+
+// CompositeCalendar - いずれかのカレンダーで休日なら休日とするカレンダー
+type CompositeCalendar []Calendar
+
+// IsHoliday - いずれかのカレンダーで休日なら休日
+func (c CompositeCalendar) IsHoliday(d time.Time) bool {
+	for _, calendar := range c {
+		if calendar != nil && calendar.IsHoliday(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// date - 時刻を持たない年月日
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func dateOf(t time.Time) date {
+	y, m, d := t.Date()
+	return date{year: y, month: m, day: d}
+}
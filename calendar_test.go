@@ -0,0 +1,84 @@
+package gotimer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_StaticCalendar_IsHoliday(t *testing.T) {
+	t.Parallel()
+	calendar := NewStaticCalendar(
+		time.Date(2021, 1, 1, 0, 0, 0, 0, time.Local),
+		time.Date(2021, 1, 11, 15, 0, 0, 0, time.Local))
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{name: "一覧にある日付ならtrue", date: time.Date(2021, 1, 1, 9, 0, 0, 0, time.Local), want: true},
+		{name: "時刻が違っても一覧にある日付ならtrue", date: time.Date(2021, 1, 11, 0, 0, 0, 0, time.Local), want: true},
+		{name: "一覧にない日付ならfalse", date: time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local), want: false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := calendar.IsHoliday(test.date)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_WeekendCalendar_IsHoliday(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{name: "金曜日ならfalse", date: time.Date(2021, 1, 8, 0, 0, 0, 0, time.Local), want: false},
+		{name: "土曜日ならtrue", date: time.Date(2021, 1, 9, 0, 0, 0, 0, time.Local), want: true},
+		{name: "日曜日ならtrue", date: time.Date(2021, 1, 10, 0, 0, 0, 0, time.Local), want: true},
+		{name: "月曜日ならfalse", date: time.Date(2021, 1, 11, 0, 0, 0, 0, time.Local), want: false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := WeekendCalendar{}.IsHoliday(test.date)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_CompositeCalendar_IsHoliday(t *testing.T) {
+	t.Parallel()
+	calendar := NewCompositeCalendar(WeekendCalendar{}, NewStaticCalendar(time.Date(2021, 1, 11, 0, 0, 0, 0, time.Local)))
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{name: "どのカレンダーでも休日でなければfalse", date: time.Date(2021, 1, 8, 0, 0, 0, 0, time.Local), want: false},
+		{name: "1つ目のカレンダーで休日ならtrue", date: time.Date(2021, 1, 9, 0, 0, 0, 0, time.Local), want: true},
+		{name: "2つ目のカレンダーで休日ならtrue", date: time.Date(2021, 1, 11, 0, 0, 0, 0, time.Local), want: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := calendar.IsHoliday(test.date)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
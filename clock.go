@@ -0,0 +1,204 @@
+package gotimer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock - 現在時刻の取得とタイマーの生成を抽象化したもの
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) ClockTimer
+}
+
+// ClockTimer - Clockが生成するタイマー
+type ClockTimer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// realClock - timeパッケージをそのまま使うClock
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) ClockTimer {
+	return &realTimer{timer: time.NewTimer(d)}
+}
+
+// realTimer - time.TimerをClockTimerとして扱うためのラッパー
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t *realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t *realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+// NewFakeClock - 指定した時刻で止まっているFakeClockを返す
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mtx)
+	return c
+}
+
+// FakeClock - AdvanceかSetを呼ぶまで時刻が進まないテスト用のClock
+type FakeClock struct {
+	now    time.Time
+	timers []*fakeTimer
+	mtx    sync.Mutex
+	cond   *sync.Cond
+}
+
+// Now - 現在時刻を返す
+func (c *FakeClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.now
+}
+
+// NewTimer - d後に発火するタイマーを返す
+//   dが0以下ならすぐに発火する
+func (c *FakeClock) NewTimer(d time.Duration) ClockTimer {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	ft := &fakeTimer{clock: c, deadline: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		ft.ch <- c.now
+		return ft
+	}
+	c.timers = append(c.timers, ft)
+	c.cond.Broadcast()
+	return ft
+}
+
+// Advance - 時刻をdだけ進め、期限の来たタイマーを期限の早い順に発火する
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mtx.Lock()
+	now := c.now.Add(d)
+	c.mtx.Unlock()
+	c.Set(now)
+}
+
+// Set - 時刻をnowにし、期限の来たタイマーを期限の早い順に発火する
+func (c *FakeClock) Set(now time.Time) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.now = now
+	sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].deadline.Before(c.timers[j].deadline) })
+	var pending []*fakeTimer
+	for _, ft := range c.timers {
+		if ft.deadline.After(now) {
+			pending = append(pending, ft)
+			continue
+		}
+		ft.ch <- now
+	}
+	c.timers = pending
+}
+
+// BlockUntil - 発火待ちのタイマーがn個以上になるまで待つ
+//   別goroutineで動いているTimerが次の実行を待ち始めたことを確認するのに使う
+func (c *FakeClock) BlockUntil(n int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// remove - 発火待ちのタイマーから取り除く
+func (c *FakeClock) remove(ft *fakeTimer) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for i, t := range c.timers {
+		if t == ft {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// fakeTimer - FakeClockが生成するタイマー
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	return t.clock.remove(t)
+}
+
+// withTimeout - clockの時刻でd後にキャンセルされるctxを返す
+//   0以下のdならタイムアウトしない
+func withTimeout(parent context.Context, clock Clock, d time.Duration) (*timeoutContext, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	c := &timeoutContext{Context: ctx}
+	if d <= 0 {
+		return c, cancel
+	}
+
+	c.deadline = clock.Now().Add(d)
+	tm := clock.NewTimer(d)
+	go func() {
+		select {
+		case <-tm.C():
+			c.mtx.Lock()
+			c.expired = true
+			c.mtx.Unlock()
+			cancel()
+		case <-ctx.Done():
+			tm.Stop()
+		}
+	}()
+	return c, cancel
+}
+
+// timeoutContext - Clockの時刻でタイムアウトするctx
+type timeoutContext struct {
+	context.Context
+	deadline time.Time
+	expired  bool
+	mtx      sync.Mutex
+}
+
+func (c *timeoutContext) Deadline() (time.Time, bool) {
+	if c.deadline.IsZero() {
+		return c.Context.Deadline()
+	}
+	if d, ok := c.Context.Deadline(); ok && d.Before(c.deadline) {
+		return d, true
+	}
+	return c.deadline, true
+}
+
+func (c *timeoutContext) Err() error {
+	if c.timedOut() {
+		return context.DeadlineExceeded
+	}
+	return c.Context.Err()
+}
+
+// timedOut - 親のctxより先にタイムアウトしたか
+func (c *timeoutContext) timedOut() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.expired
+}
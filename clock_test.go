@@ -0,0 +1,70 @@
+package gotimer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_FakeClock_Now(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local)
+	clock := NewFakeClock(now)
+	clock.Advance(90 * time.Second)
+	want := time.Date(2021, 1, 4, 9, 1, 30, 0, time.Local)
+	got := clock.Now()
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_FakeClock_NewTimer(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		d       time.Duration
+		advance time.Duration
+		want    bool
+	}{
+		{name: "期限前なら発火しない", d: 10 * time.Second, advance: 9 * time.Second, want: false},
+		{name: "期限ちょうどなら発火する", d: 10 * time.Second, advance: 10 * time.Second, want: true},
+		{name: "期限を過ぎていれば発火する", d: 10 * time.Second, advance: 24 * time.Hour, want: true},
+		{name: "dが0以下ならすぐに発火する", d: -1 * time.Second, advance: 0, want: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+			tm := clock.NewTimer(test.d)
+			clock.Advance(test.advance)
+			var got bool
+			select {
+			case <-tm.C():
+				got = true
+			default:
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_FakeClock_Stop(t *testing.T) {
+	t.Parallel()
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	tm := clock.NewTimer(time.Second)
+	stopped := tm.Stop()
+	clock.Advance(time.Minute)
+	var fired bool
+	select {
+	case <-tm.C():
+		fired = true
+	default:
+	}
+	if !stopped || fired || tm.Stop() {
+		t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), true, false, stopped, fired)
+	}
+}
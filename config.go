@@ -0,0 +1,273 @@
+package gotimer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ConfigInvalidError = errors.New("invalid config")
+)
+
+// ConfigError - 設定ファイルのどのタイマーのどの項目が不正だったか
+type ConfigError struct {
+	Index int    // timersの中での位置
+	Name  string // タイマーの名前 読めなかったら空
+	Field string // 不正だった項目 特定できなければ空
+	Err   error
+}
+
+func (e *ConfigError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "timers[%d]", e.Index)
+	if e.Name != "" {
+		fmt.Fprintf(&b, " (%q)", e.Name)
+	}
+	if e.Field != "" {
+		fmt.Fprintf(&b, ": %s", e.Field)
+	}
+	fmt.Fprintf(&b, ": %v", e.Err)
+	return b.String()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// Is - ConfigErrorはすべてConfigInvalidErrorとして扱う
+func (e *ConfigError) Is(target error) bool {
+	return target == ConfigInvalidError
+}
+
+// Config - 設定ファイルに書いたタイマーの一覧
+type Config struct {
+	Timers []TimerConfig `json:"timers"`
+}
+
+// TimerConfig - 設定ファイルに書いた1つのタイマー
+//   weekdaysは、曜日を指定していない期間に開始する曜日として使う
+type TimerConfig struct {
+	Name     string   `json:"name"`
+	Task     string   `json:"task,omitempty"` // 登録されたタスクの名前 空ならnameと同じ
+	Terms    []Term   `json:"terms,omitempty"`
+	Interval string   `json:"interval"` // time.ParseDurationの形式
+	StartNow bool     `json:"start_now,omitempty"`
+	Parallel bool     `json:"parallel,omitempty"`
+	Overlap  string   `json:"overlap,omitempty"` // skip, queue, coalesce, parallel
+	Weekdays []string `json:"weekdays,omitempty"`
+}
+
+// overlapPolicies - 設定ファイルに書ける多重実行の扱い
+var overlapPolicies = map[string]OverlapPolicy{
+	"skip":     OverlapSkip,
+	"queue":    OverlapQueue,
+	"coalesce": OverlapCoalesce,
+	"parallel": OverlapParallel,
+}
+
+// LoadConfig - JSONの設定ファイルを読み込んで検証する
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig - JSONの設定を読み込んで検証する
+//   不正なタイマーがあれば、タイマーや項目ごとのConfigErrorをerrors.Joinでまとめたerrorを返す
+//   読み込めなかったタイマーがあっても、読み込めたタイマーはすべて検証する
+func ParseConfig(data []byte) (*Config, error) {
+	var raw struct {
+		Timers []json.RawMessage `json:"timers"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ConfigInvalidError, err)
+	}
+
+	config := &Config{Timers: make([]TimerConfig, len(raw.Timers))}
+	var errs []error
+	names := map[string]int{}
+	for i, entry := range raw.Timers {
+		if err := decodeTimerConfig(entry, &config.Timers[i]); err != nil {
+			var name struct {
+				Name string `json:"name"`
+			}
+			_ = json.Unmarshal(entry, &name)
+			errs = append(errs, &ConfigError{Index: i, Name: name.Name, Field: decodeErrorField(entry, err), Err: err})
+			continue
+		}
+		errs = append(errs, config.Timers[i].validate(i, names)...)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return config, nil
+}
+
+// decodeTimerConfig - 知らない項目を許さずに、1つのタイマーの設定を読み込む
+func decodeTimerConfig(data []byte, c *TimerConfig) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(c)
+}
+
+// decodeErrorField - 読み込みのerrorから不正だった項目を返す
+//   期間が不正なら、何番目の期間が不正だったかも返す
+func decodeErrorField(data []byte, err error) string {
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &typeErr):
+		return typeErr.Field
+	case errors.Is(err, TermInvalidFormatError), errors.Is(err, TimeInvalidFormatError):
+		var v struct {
+			Terms []json.RawMessage `json:"terms"`
+		}
+		if json.Unmarshal(data, &v) == nil {
+			for i, raw := range v.Terms {
+				var term Term
+				if json.Unmarshal(raw, &term) != nil {
+					return fmt.Sprintf("terms[%d]", i)
+				}
+			}
+		}
+		return "terms"
+	}
+	return ""
+}
+
+// validate - すべてのタイマーの設定を検証する
+func (c *Config) validate() []error {
+	var errs []error
+	names := map[string]int{}
+	for i := range c.Timers {
+		errs = append(errs, c.Timers[i].validate(i, names)...)
+	}
+	return errs
+}
+
+// validate - timersのi番目にあるタイマーの設定を検証する
+//   namesには、それより前のタイマーの名前と位置を入れておき、名前の重複を調べる
+func (c *TimerConfig) validate(i int, names map[string]int) []error {
+	var errs []error
+	fail := func(field string, err error) {
+		errs = append(errs, &ConfigError{Index: i, Name: c.Name, Field: field, Err: err})
+	}
+
+	if c.Name == "" {
+		fail("name", errors.New("name is required"))
+	} else if j, ok := names[c.Name]; ok {
+		fail("name", fmt.Errorf("duplicate of timers[%d]", j))
+	} else {
+		names[c.Name] = i
+	}
+	if _, err := c.interval(); err != nil {
+		fail("interval", err)
+	}
+	if _, err := c.weekdays(); err != nil {
+		fail("weekdays", err)
+	}
+	if _, err := c.overlapPolicy(); err != nil {
+		fail("overlap", err)
+	}
+	return errs
+}
+
+// Build - 設定のタイマーを、名前で登録されたタスクと組み合わせてスケジューラに追加する
+//   tasksにないタスクを使うタイマーがあれば、タイマーごとのConfigErrorをerrors.Joinでまとめたerrorを返す
+func (c *Config) Build(tasks map[string]func(ctx context.Context) error) (*Scheduler, error) {
+	if errs := c.validate(); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	scheduler := NewScheduler()
+	var errs []error
+	for i, tc := range c.Timers {
+		task, ok := tasks[tc.taskName()]
+		if !ok || task == nil {
+			errs = append(errs, &ConfigError{Index: i, Name: tc.Name, Field: "task",
+				Err: fmt.Errorf("task %q is not registered", tc.taskName())})
+			continue
+		}
+		timer, interval := tc.timer()
+		if err := scheduler.Add(tc.Name, timer, interval, task); err != nil {
+			errs = append(errs, &ConfigError{Index: i, Name: tc.Name, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return scheduler, nil
+}
+
+// timer - 検証済みの設定からTimerとintervalを作る
+func (c *TimerConfig) timer() (*Timer, time.Duration) {
+	interval, _ := c.interval()
+	days, _ := c.weekdays()
+	policy, _ := c.overlapPolicy()
+
+	timer := new(Timer).SetStartNow(c.StartNow).SetParallelRunnable(c.Parallel)
+	if c.Overlap != "" {
+		timer.SetOverlapPolicy(policy)
+	}
+	terms := c.Terms
+	if len(terms) == 0 && len(days) > 0 {
+		terms = []Term{NewTerm(NewTime(0, 0, 0), NewTime(23, 59, 59))}
+	}
+	for _, term := range terms {
+		if term.weekdays == 0 {
+			term.weekdays = newWeekdays(days)
+		}
+		timer.AddTerm(term)
+	}
+	return timer, interval
+}
+
+// taskName - 使うタスクの名前を返す
+func (c *TimerConfig) taskName() string {
+	if c.Task == "" {
+		return c.Name
+	}
+	return c.Task
+}
+
+// interval - 実行間隔を返す
+func (c *TimerConfig) interval() (time.Duration, error) {
+	if c.Interval == "" {
+		return 0, TimerNotSetIntervalError
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("interval must be positive: %s", c.Interval)
+	}
+	return d, nil
+}
+
+// weekdays - 期間に使う曜日を返す
+func (c *TimerConfig) weekdays() ([]time.Weekday, error) {
+	return parseWeekdays(strings.Join(c.Weekdays, ","))
+}
+
+// overlapPolicy - 多重実行の扱いを返す
+func (c *TimerConfig) overlapPolicy() (OverlapPolicy, error) {
+	if c.Overlap == "" {
+		return OverlapSkip, nil
+	}
+	policy, ok := overlapPolicies[strings.ToLower(c.Overlap)]
+	if !ok {
+		return 0, fmt.Errorf("unknown overlap policy %q", c.Overlap)
+	}
+	if c.Parallel && policy != OverlapParallel {
+		return 0, fmt.Errorf("overlap %q conflicts with parallel", c.Overlap)
+	}
+	return policy, nil
+}
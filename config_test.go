@@ -0,0 +1,158 @@
+package gotimer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_ParseConfig(t *testing.T) {
+	t.Parallel()
+	data := []byte(`{"timers": [
+		{"name": "report", "terms": ["09:00-15:00"], "interval": "1h", "start_now": true, "weekdays": ["Mon-Fri"]},
+		{"name": "sync", "task": "report", "terms": [{"start": "22:00", "stop": "02:00", "weekdays": ["Sat"]}], "interval": "30m", "overlap": "queue"}
+	]}`)
+	want := &Config{Timers: []TimerConfig{
+		{Name: "report", Terms: []Term{NewTerm(NewTime(9, 0, 0), NewTime(15, 0, 0))}, Interval: "1h", StartNow: true, Weekdays: []string{"Mon-Fri"}},
+		{Name: "sync", Task: "report", Terms: []Term{NewWeekdayTerm(NewTime(22, 0, 0), NewTime(2, 0, 0), time.Saturday)}, Interval: "30m", Overlap: "queue"},
+	}}
+	got, err := ParseConfig(data)
+	if err != nil || !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v, %+v\n", t.Name(), want, got, err)
+	}
+}
+
+func Test_ParseConfig_Error(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		data string
+		want []ConfigError
+	}{
+		{name: "期間が不正ならその位置と項目",
+			data: `{"timers": [{"name": "ok", "interval": "1m"}, {"name": "bad", "terms": ["09:00-15:00", "09:00-25:00"], "interval": "1m"}]}`,
+			want: []ConfigError{{Index: 1, Name: "bad", Field: "terms[1]"}}},
+		{name: "読み込めないタイマーがあっても、他のタイマーも検証する",
+			data: `{"timers": [
+				{"name": "a", "terms": ["09:00-25:00"], "interval": "1m"},
+				{"name": "b"},
+				{"name": "b", "interval": "1m"}
+			]}`,
+			want: []ConfigError{
+				{Index: 0, Name: "a", Field: "terms[0]"},
+				{Index: 1, Name: "b", Field: "interval"},
+				{Index: 2, Name: "b", Field: "name"},
+			}},
+		{name: "型が違えばその位置と項目",
+			data: `{"timers": [{"name": "bad", "interval": 60}]}`,
+			want: []ConfigError{{Index: 0, Name: "bad", Field: "interval"}}},
+		{name: "知らない項目があればその位置",
+			data: `{"timers": [{"name": "bad", "interval": "1m", "foo": 1}]}`,
+			want: []ConfigError{{Index: 0, Name: "bad"}}},
+		{name: "検証のエラーはタイマーごと項目ごとにすべて返す",
+			data: `{"timers": [
+				{"interval": "1m"},
+				{"name": "a", "interval": "-1m", "weekdays": ["Foo"]},
+				{"name": "a", "interval": "1m", "overlap": "skip", "parallel": true}
+			]}`,
+			want: []ConfigError{
+				{Index: 0, Field: "name"},
+				{Index: 1, Name: "a", Field: "interval"},
+				{Index: 1, Name: "a", Field: "weekdays"},
+				{Index: 2, Name: "a", Field: "name"},
+				{Index: 2, Name: "a", Field: "overlap"},
+			}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseConfig([]byte(test.data))
+			var joined interface{ Unwrap() []error }
+			if !errors.Is(err, ConfigInvalidError) || !errors.As(err, &joined) {
+				t.Fatalf("%s error\ngot: %+v\n", t.Name(), err)
+			}
+			var got []ConfigError
+			for _, e := range joined.Unwrap() {
+				var ce *ConfigError
+				if errors.As(e, &ce) {
+					got = append(got, ConfigError{Index: ce.Index, Name: ce.Name, Field: ce.Field})
+				}
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n%v\n", t.Name(), test.want, got, err)
+			}
+		})
+	}
+}
+
+func Test_ConfigError_Error(t *testing.T) {
+	t.Parallel()
+	err := &ConfigError{Index: 2, Name: "report", Field: "interval", Err: errors.New("not set interval")}
+	want := `timers[2] ("report"): interval: not set interval`
+	if got := err.Error(); want != got {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_LoadConfig(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "timers.json")
+	if err := os.WriteFile(path, []byte(`{"timers": [{"name": "report", "interval": "1m"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	want := &Config{Timers: []TimerConfig{{Name: "report", Interval: "1m"}}}
+	got, err := LoadConfig(path)
+	if err != nil || !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v, %+v\n", t.Name(), want, got, err)
+	}
+}
+
+func Test_Config_Build(t *testing.T) {
+	t.Parallel()
+	config := &Config{Timers: []TimerConfig{
+		{Name: "report", Terms: []Term{NewTerm(NewTime(9, 0, 0), NewTime(15, 0, 0))}, Interval: "1h", StartNow: true,
+			Weekdays: []string{"Mon", "Tue"}},
+		{Name: "sync", Task: "report", Interval: "30m", Parallel: true},
+	}}
+	scheduler, err := config.Build(map[string]func(ctx context.Context) error{
+		"report": func(context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, _ := scheduler.Get("report")
+	sync, _ := scheduler.Get("sync")
+	want := []interface{}{
+		[]string{"report", "sync"},
+		[]Term{NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Monday, time.Tuesday)}, true, time.Hour, OverlapSkip,
+		[]Term{NewTerm(NewTime(0, 0, 0), NewTime(23, 59, 59))}, false, 30 * time.Minute, OverlapParallel,
+	}
+	got := []interface{}{
+		scheduler.List(),
+		report.terms, report.startNow, report.interval, report.overlapPolicy,
+		sync.terms, sync.startNow, sync.interval, sync.overlapPolicy,
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Config_Build_Error(t *testing.T) {
+	t.Parallel()
+	config := &Config{Timers: []TimerConfig{
+		{Name: "report", Interval: "1m"},
+		{Name: "unknown", Interval: "1m"},
+	}}
+	_, err := config.Build(map[string]func(ctx context.Context) error{"report": func(context.Context) error { return nil }})
+	var ce *ConfigError
+	if !errors.As(err, &ce) || ce.Index != 1 || ce.Name != "unknown" || ce.Field != "task" {
+		t.Errorf("%s error\ngot: %+v\n", t.Name(), err)
+	}
+}
@@ -0,0 +1,257 @@
+package gotimer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	CronInvalidSpecError = errors.New("invalid cron spec")
+)
+
+// cronDescriptors - @から始まる定義済みのスケジュール
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// cronField - cronの各フィールドの範囲と名前
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronSecond = cronField{name: "second", min: 0, max: 59}
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}}
+	cronDow = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}}
+)
+
+// ParseCron - cron形式の文字列をスケジュールにする
+//   "分 時 日 月 曜日"の5フィールドか、先頭に秒を加えた6フィールドと、@hourlyなどの定義済みのスケジュールを受け付ける
+func ParseCron(spec string) (*CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@") {
+		s, ok := cronDescriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q: unknown descriptor", CronInvalidSpecError, spec)
+		}
+		return ParseCron(s)
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w: %q: expected 5 or 6 fields, got %d", CronInvalidSpecError, spec, len(fields))
+	}
+
+	var c CronSchedule
+	var err error
+	targets := []struct {
+		field cronField
+		bits  *uint64
+	}{
+		{field: cronSecond, bits: &c.second},
+		{field: cronMinute, bits: &c.minute},
+		{field: cronHour, bits: &c.hour},
+		{field: cronDom, bits: &c.dom},
+		{field: cronMonth, bits: &c.month},
+		{field: cronDow, bits: &c.dow},
+	}
+	for i, target := range targets {
+		if *target.bits, err = target.field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("%w: %q: %s", CronInvalidSpecError, spec, err)
+		}
+	}
+	// 曜日の7は日曜日として扱う
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	c.hourAny = fields[2] == "*"
+	c.domAny = fields[3] == "*" || fields[3] == "?"
+	c.dowAny = fields[5] == "*" || fields[5] == "?"
+	return &c, nil
+}
+
+// parse - フィールドの文字列を、該当する値のビットにする
+func (f cronField) parse(s string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(s, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: invalid step %q", f.name, part[i+1:])
+			}
+			rng, step = part[:i], n
+		}
+
+		start, end := f.min, f.max
+		switch {
+		case rng == "*" || rng == "?":
+		case strings.Contains(rng, "-"):
+			i := strings.Index(rng, "-")
+			var err error
+			if start, err = f.value(rng[:i]); err != nil {
+				return 0, err
+			}
+			if end, err = f.value(rng[i+1:]); err != nil {
+				return 0, err
+			}
+			if end < start {
+				return 0, fmt.Errorf("%s: invalid range %q", f.name, rng)
+			}
+		default:
+			var err error
+			if start, err = f.value(rng); err != nil {
+				return 0, err
+			}
+			if step == 1 { // 単一の値
+				end = start
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value - 数値か名前を、範囲内の値にする
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid value %q", f.name, s)
+	}
+	if v < f.min || f.max < v {
+		return 0, fmt.Errorf("%s: value %d out of range %d-%d", f.name, v, f.min, f.max)
+	}
+	return v, nil
+}
+
+// CronSchedule - cron形式で定義されたスケジュール
+type CronSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	hourAny, domAny, dowAny               bool
+}
+
+// Next - nowより後で、スケジュールに一致する最初の日時を返す
+//   5年先までに一致する日時がなければゼロ値を返す
+func (c *CronSchedule) Next(now time.Time) time.Time {
+	loc := now.Location()
+	t := now.Truncate(time.Second).Add(time.Second)
+	yearLimit := t.Year() + 5
+	added := false
+
+WRAP:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for c.month&(1<<uint(t.Month())) == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for !c.dayMatches(t) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		if t.Day() == 1 {
+			goto WRAP
+		}
+	}
+
+	// 夏時間で同じ時刻を繰り返さないよう、時分秒は経過時間で進める
+	for c.hour&(1<<uint(t.Hour())) == 0 {
+		if !added {
+			added = true
+			t = t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second)
+		}
+		t = t.Add(time.Hour)
+		if t.Hour() == 0 {
+			goto WRAP
+		}
+	}
+
+	for c.minute&(1<<uint(t.Minute())) == 0 {
+		if !added {
+			added = true
+			t = t.Add(-time.Duration(t.Second()) * time.Second)
+		}
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto WRAP
+		}
+	}
+
+	for c.second&(1<<uint(t.Second())) == 0 {
+		added = true
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto WRAP
+		}
+	}
+
+	// 時が指定されていれば、夏時間の終了で繰り返す時刻は1回目だけにする
+	if !c.hourAny && repeatedWallClock(t) {
+		added = true
+		t = t.Add(time.Second)
+		goto WRAP
+	}
+
+	return t
+}
+
+// repeatedWallClock - 夏時間の終了で、tと同じ時刻がtより前にもあったか
+func repeatedWallClock(t time.Time) bool {
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return false
+	}
+	_, offset := t.Zone()
+	_, prevOffset := start.Add(-time.Nanosecond).Zone()
+	back := time.Duration(prevOffset-offset) * time.Second
+	return back > 0 && t.Sub(start) < back
+}
+
+// dayMatches - 日と曜日が一致しているか
+//   どちらも指定されていればどちらかの一致で、どちらかが*なら両方の一致で判定する
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
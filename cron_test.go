@@ -0,0 +1,170 @@
+package gotimer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_ParseCron_Error(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{name: "空文字はerror", spec: ""},
+		{name: "フィールドが4つならerror", spec: "* * * *"},
+		{name: "フィールドが7つならerror", spec: "* * * * * * *"},
+		{name: "未知の定義済みスケジュールはerror", spec: "@every"},
+		{name: "範囲外の値はerror", spec: "60 * * * *"},
+		{name: "数値でない値はerror", spec: "a * * * *"},
+		{name: "逆順の範囲はerror", spec: "* 10-5 * * *"},
+		{name: "0のステップはerror", spec: "*/0 * * * *"},
+		{name: "0日はerror", spec: "* * 0 * *"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseCron(test.spec)
+			if got != nil || !errors.Is(err, CronInvalidSpecError) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), nil, CronInvalidSpecError, got, err)
+			}
+		})
+	}
+}
+
+func Test_CronSchedule_Next(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		spec string
+		now  time.Time
+		want time.Time
+	}{
+		{name: "5フィールドの毎分は次の分の0秒",
+			spec: "* * * * *",
+			now:  time.Date(2021, 1, 4, 9, 0, 30, 500, time.Local),
+			want: time.Date(2021, 1, 4, 9, 1, 0, 0, time.Local)},
+		{name: "6フィールドの毎秒は次の秒",
+			spec: "* * * * * *",
+			now:  time.Date(2021, 1, 4, 9, 0, 30, 500, time.Local),
+			want: time.Date(2021, 1, 4, 9, 0, 31, 0, time.Local)},
+		{name: "現在日時と一致していても次の日時を返す",
+			spec: "0 9 * * *",
+			now:  time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local),
+			want: time.Date(2021, 1, 5, 9, 0, 0, 0, time.Local)},
+		{name: "ステップは範囲の開始から数える",
+			spec: "*/15 9-10 * * *",
+			now:  time.Date(2021, 1, 4, 9, 50, 0, 0, time.Local),
+			want: time.Date(2021, 1, 4, 10, 0, 0, 0, time.Local)},
+		{name: "単一の値にステップを付けると最大値まで繰り返す",
+			spec: "5/20 * * * * *",
+			now:  time.Date(2021, 1, 4, 9, 0, 26, 0, time.Local),
+			want: time.Date(2021, 1, 4, 9, 0, 45, 0, time.Local)},
+		{name: "リストと範囲を組み合わせられる",
+			spec: "0 9,12-13 * * *",
+			now:  time.Date(2021, 1, 4, 10, 0, 0, 0, time.Local),
+			want: time.Date(2021, 1, 4, 12, 0, 0, 0, time.Local)},
+		{name: "曜日の名前で平日を指定できる",
+			spec: "30 8 * * mon-fri",
+			now:  time.Date(2021, 1, 8, 9, 0, 0, 0, time.Local),
+			want: time.Date(2021, 1, 11, 8, 30, 0, 0, time.Local)},
+		{name: "曜日の7は日曜日",
+			spec: "0 0 * * 7",
+			now:  time.Date(2021, 1, 4, 0, 0, 0, 0, time.Local),
+			want: time.Date(2021, 1, 10, 0, 0, 0, 0, time.Local)},
+		{name: "日と曜日の両方を指定するとどちらかの一致で実行する",
+			spec: "0 0 15 * fri",
+			now:  time.Date(2021, 1, 9, 0, 0, 0, 0, time.Local),
+			want: time.Date(2021, 1, 15, 0, 0, 0, 0, time.Local)},
+		{name: "月の名前で指定できて、年をまたげる",
+			spec: "0 0 1 jan *",
+			now:  time.Date(2021, 1, 4, 0, 0, 0, 0, time.Local),
+			want: time.Date(2022, 1, 1, 0, 0, 0, 0, time.Local)},
+		{name: "存在しない日付は飛ばす",
+			spec: "0 0 31 * *",
+			now:  time.Date(2021, 2, 1, 0, 0, 0, 0, time.Local),
+			want: time.Date(2021, 3, 31, 0, 0, 0, 0, time.Local)},
+		{name: "@hourlyは毎時0分",
+			spec: "@hourly",
+			now:  time.Date(2021, 1, 4, 9, 15, 0, 0, time.Local),
+			want: time.Date(2021, 1, 4, 10, 0, 0, 0, time.Local)},
+		{name: "@dailyは毎日0時",
+			spec: "@daily",
+			now:  time.Date(2021, 1, 4, 9, 15, 0, 0, time.Local),
+			want: time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local)},
+		{name: "一致する日時がなければゼロ値",
+			spec: "0 0 30 2 *",
+			now:  time.Date(2021, 1, 4, 9, 15, 0, 0, time.Local),
+			want: time.Time{}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			schedule, err := ParseCron(test.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := schedule.Next(test.now)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_CronSchedule_Next_DST(t *testing.T) {
+	t.Parallel()
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		spec string
+		now  time.Time
+		want []time.Time
+	}{
+		{name: "毎時なら夏時間の終了で2回ある01:30は両方",
+			spec: "30 * * * *",
+			now:  time.Date(2021, 11, 7, 0, 45, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2021, 11, 7, 5, 30, 0, 0, time.UTC),
+				time.Date(2021, 11, 7, 6, 30, 0, 0, time.UTC),
+				time.Date(2021, 11, 7, 7, 30, 0, 0, time.UTC),
+			}},
+		{name: "時が指定されていれば夏時間の終了で2回ある01:30は1回目だけ",
+			spec: "30 1 * * *",
+			now:  time.Date(2021, 11, 6, 0, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2021, 11, 6, 5, 30, 0, 0, time.UTC),
+				time.Date(2021, 11, 7, 5, 30, 0, 0, time.UTC),
+				time.Date(2021, 11, 8, 6, 30, 0, 0, time.UTC),
+			}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			schedule, err := ParseCron(test.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []time.Time
+			now := test.now
+			for range test.want {
+				now = schedule.Next(now)
+				got = append(got, now.UTC())
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
@@ -1,3 +1,3 @@
 module gitlab.com/tsuchinaga/gotimer
 
-go 1.15
+go 1.21
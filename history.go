@@ -0,0 +1,75 @@
+package gotimer
+
+import "time"
+
+// Outcome - 実行結果
+type Outcome int
+
+const (
+	OutcomeSuccess Outcome = iota // エラーなく終わった
+	OutcomeError                  // エラーを返した
+	OutcomeTimeout                // タイムアウトした
+	OutcomePanic                  // panicした
+	OutcomeSkipped                // 実行されなかった
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSuccess:
+		return "success"
+	case OutcomeError:
+		return "error"
+	case OutcomeTimeout:
+		return "timeout"
+	case OutcomePanic:
+		return "panic"
+	case OutcomeSkipped:
+		return "skipped"
+	}
+	return "unknown"
+}
+
+// Execution - 1回の実行の記録
+//   スキップされた実行にはStartとEndがなく、Reasonにスキップの理由が入る
+//   Reasonはスキップされた実行でだけ意味がある
+type Execution struct {
+	Scheduled time.Time
+	Start     time.Time
+	End       time.Time
+	Outcome   Outcome
+	Err       error
+	Reason    SkipReason
+}
+
+// Duration - 実行にかかった時間を返す
+func (e Execution) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+// newHistory - 最大size件の記録を持つhistoryを返す
+func newHistory(size int) *history {
+	return &history{records: make([]Execution, 0, size)}
+}
+
+// history - 古いものから捨てられる、件数に上限のある実行の記録
+type history struct {
+	records []Execution
+	head    int // 一番古い記録の位置
+}
+
+// add - 記録を追加する 上限に達していれば一番古い記録を捨てる
+func (h *history) add(e Execution) {
+	if len(h.records) < cap(h.records) {
+		h.records = append(h.records, e)
+		return
+	}
+	h.records[h.head] = e
+	h.head = (h.head + 1) % len(h.records)
+}
+
+// list - 記録を古い順に返す
+func (h *history) list() []Execution {
+	list := make([]Execution, 0, len(h.records))
+	list = append(list, h.records[h.head:]...)
+	return append(list, h.records[:h.head]...)
+}
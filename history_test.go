@@ -0,0 +1,68 @@
+package gotimer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_Outcome_String(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		outcome Outcome
+		want    string
+	}{
+		{name: "success", outcome: OutcomeSuccess, want: "success"},
+		{name: "error", outcome: OutcomeError, want: "error"},
+		{name: "timeout", outcome: OutcomeTimeout, want: "timeout"},
+		{name: "panic", outcome: OutcomePanic, want: "panic"},
+		{name: "skipped", outcome: OutcomeSkipped, want: "skipped"},
+		{name: "未定義の値はunknown", outcome: Outcome(-1), want: "unknown"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := test.outcome.String()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_history_add(t *testing.T) {
+	t.Parallel()
+	base := time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		size int
+		adds int
+		want []time.Time
+	}{
+		{name: "上限に達していなければ追加した順", size: 3, adds: 2,
+			want: []time.Time{base, base.Add(time.Minute)}},
+		{name: "上限に達したら古いものから捨てられる", size: 3, adds: 5,
+			want: []time.Time{base.Add(2 * time.Minute), base.Add(3 * time.Minute), base.Add(4 * time.Minute)}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			h := newHistory(test.size)
+			for i := 0; i < test.adds; i++ {
+				h.add(Execution{Scheduled: base.Add(time.Duration(i) * time.Minute)})
+			}
+			var got []time.Time
+			for _, e := range h.list() {
+				got = append(got, e.Scheduled)
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
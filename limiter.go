@@ -0,0 +1,44 @@
+package gotimer
+
+import "sync"
+
+// NewLimiter - 同時にn個までタスクを実行できるLimiterを返す
+//   0以下なら上限なし
+func NewLimiter(n int) *Limiter {
+	return &Limiter{max: n}
+}
+
+// Limiter - 複数のTimerで共有する、タスクの同時実行数の上限
+type Limiter struct {
+	max     int
+	running int
+	mtx     sync.Mutex
+}
+
+// Running - 実行中のタスクの数を返す
+func (l *Limiter) Running() int {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.running
+}
+
+// acquire - 上限に達していなければ実行中の数を増やしてtrueを返す
+func (l *Limiter) acquire() bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.max > 0 && l.running >= l.max {
+		return false
+	}
+	l.running++
+	return true
+}
+
+// release - 実行中の数を減らす
+func (l *Limiter) release() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.running--
+}
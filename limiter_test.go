@@ -0,0 +1,46 @@
+package gotimer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Limiter_acquire(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		max     int
+		running int
+		want1   bool
+		want2   int
+	}{
+		{name: "上限に達していなければtrue", max: 2, running: 1, want1: true, want2: 2},
+		{name: "上限に達していればfalse", max: 2, running: 2, want1: false, want2: 2},
+		{name: "上限が0なら上限なし", max: 0, running: 100, want1: true, want2: 101},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			limiter := NewLimiter(test.max)
+			limiter.running = test.running
+			got := limiter.acquire()
+			if !reflect.DeepEqual(test.want1, got) || !reflect.DeepEqual(test.want2, limiter.Running()) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.want1, test.want2, got, limiter.Running())
+			}
+		})
+	}
+}
+
+func Test_Limiter_release(t *testing.T) {
+	t.Parallel()
+	limiter := NewLimiter(1)
+	limiter.acquire()
+	limiter.release()
+	want := 0
+	got := limiter.Running()
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
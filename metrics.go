@@ -0,0 +1,284 @@
+package gotimer
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultBuckets - 実行時間と遅れのヒストグラムの、既定の区切り(秒)
+var defaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// NewMetrics - 新しいMetricsを返す
+func NewMetrics() *Metrics {
+	return &Metrics{timers: map[string]*timerMetrics{}, buckets: defaultBuckets}
+}
+
+// Metrics - Timerごとの実行回数や実行時間を集計し、Prometheusのテキスト形式で返す
+//   ObserverでTimerに設定して集計し、http.Handlerとして公開する
+//   他のObserverも設定するなら、NewMultiObserverでまとめて設定する
+type Metrics struct {
+	timers  map[string]*timerMetrics
+	buckets []float64
+	mtx     sync.Mutex
+}
+
+// SetBuckets - 実行時間と遅れのヒストグラムの区切りを秒で設定する
+//   Observerを作った後では設定が反映されない
+func (m *Metrics) SetBuckets(buckets ...float64) *Metrics {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if len(m.timers) > 0 || len(buckets) == 0 {
+		return m
+	}
+
+	m.buckets = append([]float64{}, buckets...)
+	sort.Float64s(m.buckets)
+	return m
+}
+
+// Observer - nameのラベルで集計するObserverを返す
+//   同じnameなら同じObserverを返す
+func (m *Metrics) Observer(name string) Observer {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if tm, ok := m.timers[name]; ok {
+		return tm
+	}
+	tm := &timerMetrics{stats: timerStats{
+		skipped:  map[SkipReason]int{},
+		duration: newHistogram(m.buckets),
+		lag:      newHistogram(m.buckets),
+	}}
+	m.timers[name] = tm
+	return tm
+}
+
+// ServeHTTP - 集計結果をPrometheusのテキスト形式で返す
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+// WriteTo - 集計結果をPrometheusのテキスト形式でwに書き込む
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mtx.Lock()
+	names := make([]string, 0, len(m.timers))
+	for name := range m.timers {
+		names = append(names, name)
+	}
+	timers := make([]timerStats, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		timers[i] = m.timers[name].snapshot()
+	}
+	m.mtx.Unlock()
+
+	cw := &countWriter{w: bufio.NewWriter(w)}
+	counterMetric := func(metric, help string, value func(tm timerStats) int) {
+		cw.header(metric, help, "counter")
+		for i, name := range names {
+			cw.sample(metric, labels("timer", name), float64(value(timers[i])))
+		}
+	}
+	counterMetric("gotimer_ticks_total", "Number of scheduled run times that arrived.",
+		func(tm timerStats) int { return tm.ticks })
+	counterMetric("gotimer_tasks_total", "Number of tasks run.",
+		func(tm timerStats) int { return tm.runs })
+
+	cw.header("gotimer_tasks_skipped_total", "Number of scheduled runs skipped, by reason.", "counter")
+	for i, name := range names {
+		reasons := make([]SkipReason, 0, len(timers[i].skipped))
+		for reason := range timers[i].skipped {
+			reasons = append(reasons, reason)
+		}
+		sort.Slice(reasons, func(a, b int) bool { return reasons[a] < reasons[b] })
+		for _, reason := range reasons {
+			cw.sample("gotimer_tasks_skipped_total", labels("timer", name, "reason", reason.String()), float64(timers[i].skipped[reason]))
+		}
+	}
+
+	counterMetric("gotimer_task_panics_total", "Number of tasks that panicked.",
+		func(tm timerStats) int { return tm.panics })
+	counterMetric("gotimer_task_errors_total", "Number of tasks that returned an error or timed out.",
+		func(tm timerStats) int { return tm.errors })
+
+	histogramMetric := func(metric, help string, value func(tm timerStats) histogram) {
+		cw.header(metric, help, "histogram")
+		for i, name := range names {
+			h := value(timers[i])
+			for j, le := range h.buckets {
+				cw.sample(metric+"_bucket", labels("timer", name, "le", formatFloat(le)), float64(h.counts[j]))
+			}
+			cw.sample(metric+"_bucket", labels("timer", name, "le", "+Inf"), float64(h.count))
+			cw.sample(metric+"_sum", labels("timer", name), h.sum)
+			cw.sample(metric+"_count", labels("timer", name), float64(h.count))
+		}
+	}
+	histogramMetric("gotimer_task_duration_seconds", "Time taken by tasks.",
+		func(tm timerStats) histogram { return tm.duration })
+	histogramMetric("gotimer_schedule_lag_seconds", "Delay between the scheduled run time and the actual task start.",
+		func(tm timerStats) histogram { return tm.lag })
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// timerMetrics - 1つのTimerの集計をするObserver
+type timerMetrics struct {
+	NopObserver
+	stats timerStats
+	mtx   sync.Mutex
+}
+
+// timerStats - 1つのTimerの集計結果
+type timerStats struct {
+	ticks    int
+	runs     int
+	skipped  map[SkipReason]int
+	panics   int
+	errors   int
+	duration histogram
+	lag      histogram
+}
+
+// OnTick - 実行時刻が来た回数を数える
+func (tm *timerMetrics) OnTick(time.Time) {
+	tm.mtx.Lock()
+	defer tm.mtx.Unlock()
+
+	tm.stats.ticks++
+}
+
+// OnExecution - 実行の記録から、実行結果ごとの回数と実行時間、遅れを集計する
+func (tm *timerMetrics) OnExecution(e Execution) {
+	tm.mtx.Lock()
+	defer tm.mtx.Unlock()
+
+	if e.Outcome == OutcomeSkipped {
+		tm.stats.skipped[e.Reason]++
+		return
+	}
+
+	tm.stats.runs++
+	switch e.Outcome {
+	case OutcomePanic:
+		tm.stats.panics++
+	case OutcomeError, OutcomeTimeout:
+		tm.stats.errors++
+	}
+	tm.stats.duration.observe(e.Duration().Seconds())
+	tm.stats.lag.observe(e.Start.Sub(e.Scheduled).Seconds())
+}
+
+// snapshot - 書き込み中に変わらないよう、集計結果を複製する
+func (tm *timerMetrics) snapshot() timerStats {
+	tm.mtx.Lock()
+	defer tm.mtx.Unlock()
+
+	stats := tm.stats
+	stats.skipped = make(map[SkipReason]int, len(tm.stats.skipped))
+	for reason, n := range tm.stats.skipped {
+		stats.skipped[reason] = n
+	}
+	stats.duration = tm.stats.duration.clone()
+	stats.lag = tm.stats.lag.clone()
+	return stats
+}
+
+// newHistogram - bucketsを区切りにしたヒストグラムを返す
+func newHistogram(buckets []float64) histogram {
+	return histogram{buckets: buckets, counts: make([]int, len(buckets))}
+}
+
+// histogram - 区切りごとに、区切り以下の値の数を数える累積ヒストグラム
+type histogram struct {
+	buckets []float64
+	counts  []int
+	count   int
+	sum     float64
+}
+
+// observe - 値を1つ集計する
+func (h *histogram) observe(v float64) {
+	for i, le := range h.buckets {
+		if v <= le {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// clone - 複製を返す
+func (h histogram) clone() histogram {
+	h.counts = append([]int{}, h.counts...)
+	return h
+}
+
+// labels - 名前と値を交互に並べたものを、Prometheusのラベルの形式にする
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// labelEscaper - ラベルの値のバックスラッシュ、ダブルクォート、改行をエスケープする
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatFloat - 値をPrometheusのテキスト形式にする
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// countWriter - 書き込んだバイト数と最初のエラーを覚えておくWriter
+type countWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+// header - メトリクスのHELPとTYPEを書き込む
+func (cw *countWriter) header(metric, help, typ string) {
+	cw.write("# HELP " + metric + " " + help + "\n# TYPE " + metric + " " + typ + "\n")
+}
+
+// sample - メトリクスの値を1行書き込む
+func (cw *countWriter) sample(metric, labelSet string, value float64) {
+	cw.write(metric + labelSet + " " + formatFloat(value) + "\n")
+}
+
+func (cw *countWriter) write(s string) {
+	if cw.err != nil {
+		return
+	}
+	n, err := cw.w.WriteString(s)
+	cw.n += int64(n)
+	cw.err = err
+}
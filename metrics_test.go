@@ -0,0 +1,138 @@
+package gotimer
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_labels(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		pairs []string
+		want  string
+	}{
+		{name: "名前と値を並べる", pairs: []string{"timer", "a", "le", "0.5"}, want: `{timer="a",le="0.5"}`},
+		{name: "値をエスケープする", pairs: []string{"timer", "a\\b\"c\nd"}, want: `{timer="a\\b\"c\nd"}`},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := labels(test.pairs...)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Metrics_ServeHTTP(t *testing.T) {
+	t.Parallel()
+	metrics := NewMetrics().SetBuckets(1, 0.1)
+	scheduled := time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local)
+	o := metrics.Observer("job").(ExecutionObserver)
+	o.(TickObserver).OnTick(scheduled)
+	o.(TickObserver).OnTick(scheduled)
+	o.OnExecution(Execution{Scheduled: scheduled, Start: scheduled.Add(500 * time.Millisecond), End: scheduled.Add(550 * time.Millisecond)})
+	o.OnExecution(Execution{Scheduled: scheduled, Start: scheduled, End: scheduled.Add(2 * time.Second), Outcome: OutcomeError, Err: errors.New("error")})
+	o.OnExecution(Execution{Scheduled: scheduled, Start: scheduled, End: scheduled, Outcome: OutcomePanic})
+	o.OnExecution(Execution{Scheduled: scheduled, Outcome: OutcomeSkipped, Reason: SkipReasonOverlap})
+	metrics.Observer("idle")
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	want := strings.Join([]string{
+		`# HELP gotimer_ticks_total Number of scheduled run times that arrived.`,
+		`# TYPE gotimer_ticks_total counter`,
+		`gotimer_ticks_total{timer="idle"} 0`,
+		`gotimer_ticks_total{timer="job"} 2`,
+		`# HELP gotimer_tasks_total Number of tasks run.`,
+		`# TYPE gotimer_tasks_total counter`,
+		`gotimer_tasks_total{timer="idle"} 0`,
+		`gotimer_tasks_total{timer="job"} 3`,
+		`# HELP gotimer_tasks_skipped_total Number of scheduled runs skipped, by reason.`,
+		`# TYPE gotimer_tasks_skipped_total counter`,
+		`gotimer_tasks_skipped_total{timer="job",reason="overlap"} 1`,
+		`# HELP gotimer_task_panics_total Number of tasks that panicked.`,
+		`# TYPE gotimer_task_panics_total counter`,
+		`gotimer_task_panics_total{timer="idle"} 0`,
+		`gotimer_task_panics_total{timer="job"} 1`,
+		`# HELP gotimer_task_errors_total Number of tasks that returned an error or timed out.`,
+		`# TYPE gotimer_task_errors_total counter`,
+		`gotimer_task_errors_total{timer="idle"} 0`,
+		`gotimer_task_errors_total{timer="job"} 1`,
+		`# HELP gotimer_task_duration_seconds Time taken by tasks.`,
+		`# TYPE gotimer_task_duration_seconds histogram`,
+		`gotimer_task_duration_seconds_bucket{timer="idle",le="0.1"} 0`,
+		`gotimer_task_duration_seconds_bucket{timer="idle",le="1"} 0`,
+		`gotimer_task_duration_seconds_bucket{timer="idle",le="+Inf"} 0`,
+		`gotimer_task_duration_seconds_sum{timer="idle"} 0`,
+		`gotimer_task_duration_seconds_count{timer="idle"} 0`,
+		`gotimer_task_duration_seconds_bucket{timer="job",le="0.1"} 2`,
+		`gotimer_task_duration_seconds_bucket{timer="job",le="1"} 2`,
+		`gotimer_task_duration_seconds_bucket{timer="job",le="+Inf"} 3`,
+		`gotimer_task_duration_seconds_sum{timer="job"} 2.05`,
+		`gotimer_task_duration_seconds_count{timer="job"} 3`,
+		`# HELP gotimer_schedule_lag_seconds Delay between the scheduled run time and the actual task start.`,
+		`# TYPE gotimer_schedule_lag_seconds histogram`,
+		`gotimer_schedule_lag_seconds_bucket{timer="idle",le="0.1"} 0`,
+		`gotimer_schedule_lag_seconds_bucket{timer="idle",le="1"} 0`,
+		`gotimer_schedule_lag_seconds_bucket{timer="idle",le="+Inf"} 0`,
+		`gotimer_schedule_lag_seconds_sum{timer="idle"} 0`,
+		`gotimer_schedule_lag_seconds_count{timer="idle"} 0`,
+		`gotimer_schedule_lag_seconds_bucket{timer="job",le="0.1"} 2`,
+		`gotimer_schedule_lag_seconds_bucket{timer="job",le="1"} 3`,
+		`gotimer_schedule_lag_seconds_bucket{timer="job",le="+Inf"} 3`,
+		`gotimer_schedule_lag_seconds_sum{timer="job"} 0.5`,
+		`gotimer_schedule_lag_seconds_count{timer="job"} 3`,
+	}, "\n") + "\n"
+	got := rec.Body.String()
+	if !reflect.DeepEqual(want, got) || rec.Header().Get("Content-Type") != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v, %+v\n", t.Name(), want, got, rec.Header().Get("Content-Type"))
+	}
+}
+
+func Test_Timer_Run_Metrics(t *testing.T) {
+	t.Parallel()
+	metrics := NewMetrics()
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).SetObserver(metrics.Observer("job"))
+	release := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = timer.Run(ctx, time.Hour, func() { <-release }) }()
+
+	clock.BlockUntil(1)
+	clock.Set(time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local))
+	clock.BlockUntil(1)
+	clock.Set(time.Date(2021, 1, 5, 1, 0, 0, 0, time.Local)) // 実行中なのでスキップ
+	clock.BlockUntil(1)
+	close(release)
+	if _, err := timer.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if _, err := metrics.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`gotimer_ticks_total{timer="job"} 2`,
+		`gotimer_tasks_total{timer="job"} 1`,
+		`gotimer_tasks_skipped_total{timer="job",reason="overlap"} 1`,
+		`gotimer_task_duration_seconds_count{timer="job"} 1`,
+	}
+	for _, line := range want {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), line, b.String())
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package gotimer
+
+import "time"
+
+// Observer - タイマーの実行中に起きたことを受け取る
+//   Timerを止めないよう、各メソッドはすぐに返すようにする
+type Observer interface {
+	OnStart()                                      // タイマーが実行中になった
+	OnScheduled(next time.Time)                    // 次回実行日時が決まった
+	OnTaskStart(scheduled time.Time)               // タスクの実行を始めた
+	OnTaskEnd(duration time.Duration, err error)   // タスクの実行が終わった
+	OnSkip(scheduled time.Time, reason SkipReason) // 実行時刻が来たタスクを実行しなかった
+	OnTermEnter(term Term)                         // 実行期間に入った
+	OnTermExit(term Term)                          // 実行期間の停止日時が来たか、期間の途中でタイマーが実行中でなくなった
+	OnStop()                                       // タイマーが実行中でなくなった
+}
+
+// TickObserver - 実行時刻が来たことも受け取るObserver
+//   実行したかスキップしたかに関わらず、実行時刻が来るたびにOnTickが呼ばれる
+type TickObserver interface {
+	Observer
+	OnTick(scheduled time.Time)
+}
+
+// ExecutionObserver - 実行の記録も受け取るObserver
+//   スキップも含めて、実行の記録が残るたびにOnExecutionが呼ばれる
+type ExecutionObserver interface {
+	Observer
+	OnExecution(e Execution)
+}
+
+// NopObserver - 何もしないObserver
+//   埋め込めば、必要なメソッドだけを実装したObserverが作れる
+type NopObserver struct{}
+
+func (NopObserver) OnStart()                       {}
+func (NopObserver) OnScheduled(time.Time)          {}
+func (NopObserver) OnTaskStart(time.Time)          {}
+func (NopObserver) OnTaskEnd(time.Duration, error) {}
+func (NopObserver) OnSkip(time.Time, SkipReason)   {}
+func (NopObserver) OnTermEnter(Term)               {}
+func (NopObserver) OnTermExit(Term)                {}
+func (NopObserver) OnStop()                        {}
+
+// NewMultiObserver - 受け取ったことを、observersのすべてに順に渡すObserverを返す
+//   TickObserverかExecutionObserverを実装しているObserverには、OnTickかOnExecutionも渡す
+func NewMultiObserver(observers ...Observer) *MultiObserver {
+	m := &MultiObserver{}
+	for _, o := range observers {
+		if o != nil {
+			m.observers = append(m.observers, o)
+		}
+	}
+	return m
+}
+
+// MultiObserver - 複数のObserverをまとめて1つのTimerに設定するためのObserver
+type MultiObserver struct {
+	observers []Observer
+}
+
+func (m *MultiObserver) OnStart() {
+	for _, o := range m.observers {
+		o.OnStart()
+	}
+}
+
+func (m *MultiObserver) OnScheduled(next time.Time) {
+	for _, o := range m.observers {
+		o.OnScheduled(next)
+	}
+}
+
+func (m *MultiObserver) OnTaskStart(scheduled time.Time) {
+	for _, o := range m.observers {
+		o.OnTaskStart(scheduled)
+	}
+}
+
+func (m *MultiObserver) OnTaskEnd(duration time.Duration, err error) {
+	for _, o := range m.observers {
+		o.OnTaskEnd(duration, err)
+	}
+}
+
+func (m *MultiObserver) OnSkip(scheduled time.Time, reason SkipReason) {
+	for _, o := range m.observers {
+		o.OnSkip(scheduled, reason)
+	}
+}
+
+func (m *MultiObserver) OnTermEnter(term Term) {
+	for _, o := range m.observers {
+		o.OnTermEnter(term)
+	}
+}
+
+func (m *MultiObserver) OnTermExit(term Term) {
+	for _, o := range m.observers {
+		o.OnTermExit(term)
+	}
+}
+
+func (m *MultiObserver) OnStop() {
+	for _, o := range m.observers {
+		o.OnStop()
+	}
+}
+
+// OnTick - TickObserverを実装しているObserverにだけ渡す
+func (m *MultiObserver) OnTick(scheduled time.Time) {
+	for _, o := range m.observers {
+		if o, ok := o.(TickObserver); ok {
+			o.OnTick(scheduled)
+		}
+	}
+}
+
+// OnExecution - ExecutionObserverを実装しているObserverにだけ渡す
+func (m *MultiObserver) OnExecution(e Execution) {
+	for _, o := range m.observers {
+		if o, ok := o.(ExecutionObserver); ok {
+			o.OnExecution(e)
+		}
+	}
+}
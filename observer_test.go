@@ -0,0 +1,215 @@
+package gotimer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testObserver struct {
+	events []string
+	tasks  []string
+	mtx    sync.Mutex
+}
+
+func (o *testObserver) add(events *[]string, format string, a ...interface{}) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	*events = append(*events, fmt.Sprintf(format, a...))
+}
+
+func (o *testObserver) OnStart() { o.add(&o.events, "start") }
+func (o *testObserver) OnScheduled(next time.Time) {
+	o.add(&o.events, "scheduled %s", next.Format("01/02 15:04"))
+}
+func (o *testObserver) OnTaskStart(scheduled time.Time) {
+	o.add(&o.tasks, "task start %s", scheduled.Format("01/02 15:04"))
+}
+func (o *testObserver) OnTaskEnd(duration time.Duration, err error) {
+	o.add(&o.tasks, "task end %s %v", duration, err)
+}
+func (o *testObserver) OnSkip(scheduled time.Time, reason SkipReason) {
+	o.add(&o.events, "skip %s %s", scheduled.Format("01/02 15:04"), reason)
+}
+func (o *testObserver) OnTermEnter(term Term) { o.add(&o.events, "enter %d-%d", term.start, term.stop) }
+func (o *testObserver) OnTermExit(term Term)  { o.add(&o.events, "exit %d-%d", term.start, term.stop) }
+func (o *testObserver) OnStop()               { o.add(&o.events, "stop") }
+
+func Test_NopObserver(t *testing.T) {
+	t.Parallel()
+	var _ Observer = NopObserver{}
+	var _ Observer = struct{ NopObserver }{}
+}
+
+func Test_MultiObserver(t *testing.T) {
+	t.Parallel()
+	var _ TickObserver = &MultiObserver{}
+	var _ ExecutionObserver = &MultiObserver{}
+
+	observer := &testObserver{}
+	metrics := NewMetrics()
+	multi := NewMultiObserver(observer, nil, metrics.Observer("job"))
+	scheduled := time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local)
+	multi.OnStart()
+	multi.OnTick(scheduled)
+	multi.OnTaskStart(scheduled)
+	multi.OnTaskEnd(time.Second, nil)
+	multi.OnExecution(Execution{Scheduled: scheduled, Start: scheduled, End: scheduled.Add(time.Second), Outcome: OutcomeSuccess})
+	multi.OnStop()
+
+	// TickObserverとExecutionObserverを実装していないObserverには、OnTickとOnExecutionは渡さない
+	wantEvents := []string{"start", "stop"}
+	wantTasks := []string{"task start 01/04 09:00", "task end 1s <nil>"}
+	if !reflect.DeepEqual(wantEvents, observer.events) || !reflect.DeepEqual(wantTasks, observer.tasks) {
+		t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), wantEvents, wantTasks, observer.events, observer.tasks)
+	}
+
+	var buf strings.Builder
+	if _, err := metrics.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`gotimer_ticks_total{timer="job"} 1`, `gotimer_tasks_total{timer="job"} 1`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, buf.String())
+		}
+	}
+}
+
+func Test_Timer_Run_Observer(t *testing.T) {
+	t.Parallel()
+	taskErr := errors.New("task error")
+	observer := &testObserver{}
+	clock := NewFakeClock(time.Date(2021, 1, 4, 8, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).SetObserver(observer).AddTerm(NewTerm(NewTime(9, 0, 0), NewTime(10, 0, 0)))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	runs := 0
+	go func() {
+		defer close(done)
+		_ = timer.RunContext(ctx, 30*time.Minute, func(context.Context) error {
+			runs++
+			if runs == 2 {
+				return taskErr
+			}
+			return nil
+		})
+	}()
+
+	for _, now := range []time.Time{
+		time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local),
+		time.Date(2021, 1, 4, 9, 30, 0, 0, time.Local),
+		time.Date(2021, 1, 4, 10, 0, 0, 0, time.Local),
+	} {
+		clock.BlockUntil(1)
+		if _, err := timer.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if now.Hour() == 10 {
+			timer.Pause()
+		}
+		clock.Set(now)
+	}
+	clock.BlockUntil(1)
+	cancel()
+	<-done
+
+	wantEvents := []string{
+		"start",
+		"scheduled 01/04 09:00",
+		"enter 32400-36000",
+		"scheduled 01/04 09:30",
+		"scheduled 01/04 10:00",
+		"skip 01/04 10:00 paused",
+		"exit 32400-36000",
+		"scheduled 01/05 09:00",
+		"stop",
+	}
+	wantTasks := []string{
+		"task start 01/04 09:00",
+		"task end 0s <nil>",
+		"task start 01/04 09:30",
+		"task end 0s task error",
+	}
+	observer.mtx.Lock()
+	defer observer.mtx.Unlock()
+	if !reflect.DeepEqual(wantEvents, observer.events) || !reflect.DeepEqual(wantTasks, observer.tasks) {
+		t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), wantEvents, wantTasks, observer.events, observer.tasks)
+	}
+}
+
+// termObserver - 実行期間に入った日時と出た日時を記録するObserver
+type termObserver struct {
+	NopObserver
+	clock  *FakeClock
+	events []string
+	mtx    sync.Mutex
+}
+
+func (o *termObserver) add(event string) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.events = append(o.events, event+" "+o.clock.Now().Format("01/02 15:04"))
+}
+
+func (o *termObserver) OnTermEnter(Term) { o.add("enter") }
+func (o *termObserver) OnTermExit(Term)  { o.add("exit") }
+
+func Test_Observer_OnTermExit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		run  func(ctx context.Context, clock *FakeClock, timer *Timer)
+	}{
+		{name: "Timerなら期間の停止日時に期間から出る",
+			run: func(ctx context.Context, _ *FakeClock, timer *Timer) {
+				_ = timer.Run(ctx, time.Hour, func() {})
+			}},
+		{name: "Schedulerでも期間の停止日時に期間から出る",
+			run: func(ctx context.Context, clock *FakeClock, timer *Timer) {
+				scheduler := NewScheduler().SetClock(clock)
+				_ = scheduler.Add("job", timer, time.Hour, func(context.Context) error { return nil })
+				_ = scheduler.Run(ctx)
+			}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			clock := NewFakeClock(time.Date(2021, 1, 4, 8, 0, 0, 0, time.Local))
+			observer := &termObserver{clock: clock}
+			timer := new(Timer).SetClock(clock).SetObserver(observer).AddTerm(NewTerm(NewTime(9, 0, 0), NewTime(11, 30, 0)))
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				test.run(ctx, clock, timer)
+			}()
+
+			for _, now := range []time.Time{
+				time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local),
+				time.Date(2021, 1, 4, 10, 0, 0, 0, time.Local),
+				time.Date(2021, 1, 4, 11, 0, 0, 0, time.Local),
+				time.Date(2021, 1, 4, 11, 30, 0, 0, time.Local), // 次回実行日時は翌日なので、停止日時を待っている
+			} {
+				clock.BlockUntil(1)
+				clock.Set(now)
+			}
+			clock.BlockUntil(1)
+			cancel()
+			<-done
+
+			want := []string{"enter 01/04 09:00", "exit 01/04 11:30"}
+			observer.mtx.Lock()
+			defer observer.mtx.Unlock()
+			if !reflect.DeepEqual(want, observer.events) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, observer.events)
+			}
+		})
+	}
+}
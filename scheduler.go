@@ -0,0 +1,392 @@
+package gotimer
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	SchedulerNotSetContextError = errors.New("not set ctx")
+	SchedulerNotSetNameError    = errors.New("not set job name")
+	SchedulerJobExistsError     = errors.New("job already exists")
+	SchedulerJobNotFoundError   = errors.New("job not found")
+	SchedulerIsRunningError     = errors.New("scheduler is running now")
+)
+
+// NewScheduler - 新しいスケジューラを返す
+func NewScheduler() *Scheduler {
+	return &Scheduler{jobs: map[string]*job{}, wake: make(chan struct{}, 1)}
+}
+
+// Scheduler - 名前を付けた複数のジョブを、1つのループで実行するスケジューラ
+//   ジョブごとの期間やintervalなどの設定はTimerで行う
+type Scheduler struct {
+	jobs    map[string]*job
+	queue   jobQueue
+	clock   Clock
+	logger  *slog.Logger
+	tracer  Tracer
+	ctx     context.Context
+	running bool
+	wake    chan struct{}
+	mtx     sync.Mutex
+}
+
+// job - スケジューラに登録されたジョブ
+type job struct {
+	name    string
+	timer   *Timer
+	task    func(ctx context.Context) error
+	next    time.Time
+	at      time.Time // queueで待つ日時 次回実行日時か、Timerが今いる実行期間の停止日時
+	exiting bool      // atが実行期間の停止日時か
+	index   int       // queueの中での位置 queueになければ-1
+}
+
+// SetClock - 時刻の取得とタイマーの生成に使うClockを設定する
+//   Clockが未設定のTimerをジョブに追加すると、TimerにもこのClockを設定する
+func (s *Scheduler) SetClock(clock Clock) *Scheduler {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.running {
+		return s
+	}
+
+	s.clock = clock
+	return s
+}
+
+// SetLogger - ジョブの追加や削除を記録するロガーを設定する
+//   ロガーが未設定のTimerをジョブに追加すると、Timerにもジョブ名を付けたこのロガーを設定する
+func (s *Scheduler) SetLogger(logger *slog.Logger) *Scheduler {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.running {
+		return s
+	}
+
+	s.logger = logger
+	return s
+}
+
+// SetTracer - ジョブのタスクの実行ごとにSpanを作るトレーサーを設定する
+//   トレーサーが未設定のTimerをジョブに追加すると、Timerにもこのトレーサーを設定する
+func (s *Scheduler) SetTracer(tracer Tracer) *Scheduler {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.running {
+		return s
+	}
+
+	s.tracer = tracer
+	return s
+}
+
+// Add - ジョブを追加する
+//   timerは追加したジョブで使われ、Removeされるまで実行中になるので、他で実行することはできない
+//   スケジューラの実行中に追加したジョブは、すぐに次回実行日時を決めて実行を待つ
+func (s *Scheduler) Add(name string, timer *Timer, interval time.Duration, task func(ctx context.Context) error) error {
+	if name == "" {
+		return SchedulerNotSetNameError
+	}
+	if timer == nil {
+		timer = new(Timer)
+	}
+	if interval <= 0 && !timer.hasInterval() {
+		return TimerNotSetIntervalError
+	}
+	if task == nil {
+		return TimerNotSetTaskError
+	}
+
+	s.mtx.Lock()
+	if _, ok := s.jobs[name]; ok {
+		s.mtx.Unlock()
+		return SchedulerJobExistsError
+	}
+	if timer.clock == nil && s.clock != nil {
+		timer.SetClock(s.clock)
+	}
+	if timer.logger == nil && s.logger != nil {
+		timer.SetLogger(s.logger.With(slog.String("job", name)))
+	}
+	if timer.tracer == nil && s.tracer != nil {
+		timer.SetTracer(s.tracer)
+	}
+	if timer.name == "" {
+		timer.SetName(name)
+	}
+	started, err := timer.start(interval, nil)
+	if err != nil {
+		s.mtx.Unlock()
+		return err
+	}
+
+	j := &job{name: name, timer: timer, task: task, index: -1}
+	s.jobs[name] = j
+	scheduled := func() {}
+	if s.running {
+		s.bind(j)
+		scheduled = s.schedule(j, s.getClock().Now())
+		s.notify()
+	}
+	s.mtx.Unlock()
+
+	// Observerやロガーからスケジューラを操作できるように、ロックを外して知らせる
+	started()
+	s.log(slog.LevelInfo, "job added", slog.String("job", name))
+	scheduled()
+	return nil
+}
+
+// Remove - ジョブを削除する
+//   実行中のタスクは止めず、削除したジョブのTimerは実行中でなくなる
+func (s *Scheduler) Remove(name string) error {
+	s.mtx.Lock()
+	j, ok := s.jobs[name]
+	if !ok {
+		s.mtx.Unlock()
+		return SchedulerJobNotFoundError
+	}
+	delete(s.jobs, name)
+	if j.index >= 0 {
+		heap.Remove(&s.queue, j.index)
+	}
+	s.notify()
+	s.mtx.Unlock()
+
+	// Observerやロガーからスケジューラを操作できるように、ロックを外して止める
+	j.timer.finish()
+	s.log(slog.LevelInfo, "job removed", slog.String("job", name))
+	return nil
+}
+
+// List - 登録されているジョブの名前を昇順で返す
+func (s *Scheduler) List() []string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get - ジョブのTimerを返す
+func (s *Scheduler) Get(name string) (*Timer, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return nil, false
+	}
+	return j.timer, true
+}
+
+// Run - スケジューラの開始
+//   次回実行日時の早い順に並べたジョブを1つのループで待ち、実行時刻が来たジョブのタスクを実行する
+func (s *Scheduler) Run(ctx context.Context) error {
+	if ctx == nil {
+		return SchedulerNotSetContextError
+	}
+
+	s.mtx.Lock()
+	if s.running {
+		s.mtx.Unlock()
+		return SchedulerIsRunningError
+	}
+	s.running = true
+	s.ctx = ctx
+	clock := s.getClock()
+	now := clock.Now()
+	var scheduled []func()
+	for _, j := range s.jobs {
+		s.bind(j)
+		scheduled = append(scheduled, s.schedule(j, now))
+	}
+	s.mtx.Unlock()
+	s.log(slog.LevelInfo, "scheduler started")
+	for _, notify := range scheduled {
+		notify()
+	}
+
+	defer func() {
+		s.log(slog.LevelInfo, "scheduler stopped")
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		s.running = false
+		s.ctx = nil
+		s.queue = nil
+		for _, j := range s.jobs {
+			j.index = -1
+			j.timer.bind(nil, nil, nil)
+		}
+	}()
+
+	for {
+		s.mtx.Lock()
+		var c <-chan time.Time
+		var tm ClockTimer
+		if len(s.queue) > 0 {
+			tm = clock.NewTimer(s.queue[0].at.Sub(clock.Now()))
+			c = tm.C()
+		}
+		s.mtx.Unlock()
+
+		select {
+		case <-c: // 先頭のジョブの待つ日時が来たら、待つ日時を過ぎたジョブをすべて実行するか期間から出す
+			s.runDue(ctx, clock.Now())
+		case <-s.wake: // ジョブが追加か削除されたら、先頭のジョブを選び直す
+			if tm != nil {
+				tm.Stop()
+			}
+		case <-ctx.Done():
+			if tm != nil {
+				tm.Stop()
+			}
+			return nil
+		}
+	}
+}
+
+// runDue - 実行時刻を過ぎたジョブのタスクを実行し、次回実行日時を決め直す
+//   実行期間の停止日時を過ぎたジョブは、期間から出して同じ次回実行日時を待ち直す
+func (s *Scheduler) runDue(ctx context.Context, now time.Time) {
+	s.mtx.Lock()
+	var due []*job
+	for len(s.queue) > 0 && !s.queue[0].at.After(now) {
+		due = append(due, heap.Pop(&s.queue).(*job))
+	}
+	s.mtx.Unlock()
+
+	// スキップハンドラなどからスケジューラを操作できるように、ロックを外して実行する
+	for _, j := range due {
+		if j.exiting {
+			j.timer.exitTerm(now)
+		} else {
+			j.timer.dispatch(ctx, j.next, j.task)
+		}
+	}
+
+	s.mtx.Lock()
+	var scheduled []func()
+	for _, j := range due {
+		// 実行中に削除されたジョブと、実行中に決め直されたジョブは戻さない
+		if s.jobs[j.name] != j || j.index >= 0 {
+			continue
+		}
+		if j.exiting {
+			s.push(j)
+		} else {
+			scheduled = append(scheduled, s.schedule(j, now))
+		}
+	}
+	s.mtx.Unlock()
+	for _, notify := range scheduled {
+		notify()
+	}
+}
+
+// bind - ジョブのTimerに、スケジューラのctxとタスク、次回実行日時を決め直す方法を設定する
+func (s *Scheduler) bind(j *job) {
+	j.timer.bind(s.ctx, j.task, func() { s.reschedule(j) })
+}
+
+// reschedule - ジョブの次回実行日時を決め直す
+func (s *Scheduler) reschedule(j *job) {
+	s.mtx.Lock()
+	if !s.running || s.jobs[j.name] != j {
+		s.mtx.Unlock()
+		return
+	}
+	if j.index >= 0 {
+		heap.Remove(&s.queue, j.index)
+	}
+	scheduled := s.schedule(j, s.getClock().Now())
+	s.notify()
+	s.mtx.Unlock()
+
+	scheduled()
+}
+
+// schedule - ジョブの次回実行日時を決めてqueueに入れ、Observerとロガーに知らせる関数を返す
+//   知らせる関数は、ロックを外してから呼ぶ
+func (s *Scheduler) schedule(j *job, now time.Time) func() {
+	next, scheduled := j.timer.advance(now)
+	j.next = next
+	s.push(j)
+	return scheduled
+}
+
+// push - ジョブを、次回実行日時か実行期間の停止日時の早い方でqueueに入れる
+//   どちらもなければqueueに入れない
+func (s *Scheduler) push(j *job) {
+	j.at, j.exiting = j.timer.wakeAt(j.next)
+	if !j.at.IsZero() {
+		heap.Push(&s.queue, j)
+	}
+}
+
+// notify - ループにジョブの変更を知らせる
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// log - ロガーが設定されていれば記録を残す
+func (s *Scheduler) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	if s.logger == nil {
+		return
+	}
+	s.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// getClock - 設定されたClockを返す、未設定ならtimeパッケージを使うClockを返す
+func (s *Scheduler) getClock() Clock {
+	if s.clock == nil {
+		return realClock{}
+	}
+	return s.clock
+}
+
+// jobQueue - 待つ日時の早い順に並ぶジョブの優先度付きキュー
+type jobQueue []*job
+
+func (q jobQueue) Len() int { return len(q) }
+
+func (q jobQueue) Less(i, j int) bool { return q[i].at.Before(q[j].at) }
+
+func (q jobQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *jobQueue) Push(x interface{}) {
+	j := x.(*job)
+	j.index = len(*q)
+	*q = append(*q, j)
+}
+
+func (q *jobQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	j := old[n-1]
+	old[n-1] = nil
+	j.index = -1
+	*q = old[:n-1]
+	return j
+}
@@ -0,0 +1,197 @@
+package gotimer
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func Test_Scheduler_Add(t *testing.T) {
+	t.Parallel()
+	task := func(context.Context) error { return nil }
+	tests := []struct {
+		name     string
+		jobName  string
+		timer    *Timer
+		interval time.Duration
+		task     func(ctx context.Context) error
+		want     error
+	}{
+		{name: "名前が空ならerror", jobName: "", interval: time.Minute, task: task, want: SchedulerNotSetNameError},
+		{name: "intervalが1未満ならerror", jobName: "job", interval: 0, task: task, want: TimerNotSetIntervalError},
+		{name: "taskがnilならerror", jobName: "job", interval: time.Minute, want: TimerNotSetTaskError},
+		{name: "同じ名前のジョブがあればerror", jobName: "exists", interval: time.Minute, task: task, want: SchedulerJobExistsError},
+		{name: "Timerが実行中ならerror", jobName: "job", timer: &Timer{timerRunning: true}, interval: time.Minute, task: task, want: TimerIsRunningError},
+		{name: "timerがnilでも追加できる", jobName: "job", interval: time.Minute, task: task, want: nil},
+		{name: "スケジュールがあればintervalが0でも追加できる", jobName: "job", timer: (&Timer{}).SetSchedule(&CronSchedule{}), task: task, want: nil},
+		{name: "SetIntervalで設定されていればintervalが0でも追加できる", jobName: "job", timer: &Timer{interval: time.Minute, intervalSet: true}, task: task, want: nil},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			scheduler := NewScheduler()
+			_ = scheduler.Add("exists", nil, time.Minute, task)
+			got := scheduler.Add(test.jobName, test.timer, test.interval, test.task)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Scheduler_Remove(t *testing.T) {
+	t.Parallel()
+	scheduler := NewScheduler()
+	timer := new(Timer)
+	_ = scheduler.Add("job", timer, time.Minute, func(context.Context) error { return nil })
+
+	got1 := scheduler.Remove("job")
+	got2 := scheduler.Remove("job")
+	_, got3 := scheduler.Get("job")
+	if got1 != nil || got2 != SchedulerJobNotFoundError || got3 || timer.timerRunning {
+		t.Errorf("%s error\nwant: %+v, %+v, %+v, %+v\ngot: %+v, %+v, %+v, %+v\n", t.Name(),
+			nil, SchedulerJobNotFoundError, false, false, got1, got2, got3, timer.timerRunning)
+	}
+}
+
+func Test_Scheduler_List(t *testing.T) {
+	t.Parallel()
+	scheduler := NewScheduler()
+	for _, name := range []string{"c", "a", "b"} {
+		_ = scheduler.Add(name, nil, time.Minute, func(context.Context) error { return nil })
+	}
+	want := []string{"a", "b", "c"}
+	got := scheduler.List()
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Scheduler_Get(t *testing.T) {
+	t.Parallel()
+	scheduler := NewScheduler()
+	timer := new(Timer)
+	_ = scheduler.Add("job", timer, time.Minute, func(context.Context) error { return nil })
+	got, ok := scheduler.Get("job")
+	if got != timer || !ok {
+		t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), timer, true, got, ok)
+	}
+}
+
+func Test_Scheduler_Run(t *testing.T) {
+	t.Parallel()
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	scheduler := NewScheduler().SetClock(clock)
+	ch := make(chan string, 10)
+	task := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			ch <- name
+			return nil
+		}
+	}
+	_ = scheduler.Add("hourly", nil, time.Hour, task("hourly"))
+	_ = scheduler.Add("half", nil, 30*time.Minute, task("half"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = scheduler.Run(ctx) }()
+
+	steps := []struct {
+		now    time.Time
+		before func()
+		want   []string
+	}{
+		{now: time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local), want: []string{"half", "hourly"}},
+		{now: time.Date(2021, 1, 5, 0, 30, 0, 0, time.Local), want: []string{"half"}},
+		{now: time.Date(2021, 1, 5, 1, 0, 0, 0, time.Local), want: []string{"half", "hourly"},
+			before: func() {
+				_ = scheduler.Add("added", (&Timer{}).AddTerm(NewTerm(NewTime(2, 0, 0), NewTime(23, 59, 59))), time.Hour, task("added"))
+			}},
+		{now: time.Date(2021, 1, 5, 1, 30, 0, 0, time.Local), want: []string{"half"}},
+		{now: time.Date(2021, 1, 5, 2, 0, 0, 0, time.Local), want: []string{"added", "half"},
+			before: func() { _ = scheduler.Remove("hourly") }},
+	}
+	for _, step := range steps {
+		clock.BlockUntil(1)
+		if step.before != nil {
+			step.before()
+		}
+		clock.Set(step.now)
+		var got []string
+		for range step.want {
+			got = append(got, <-ch)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(step.want, got) {
+			t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), step.want, got)
+		}
+	}
+}
+
+// listingObserver - 受け取るたびにスケジューラのジョブの一覧を取るObserver
+type listingObserver struct {
+	NopObserver
+	scheduler *Scheduler
+	ch        chan []string
+}
+
+func (o *listingObserver) OnScheduled(time.Time) { o.ch <- o.scheduler.List() }
+func (o *listingObserver) OnStop()               { o.ch <- o.scheduler.List() }
+
+func Test_Scheduler_Run_Observer(t *testing.T) {
+	t.Parallel()
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	scheduler := NewScheduler().SetClock(clock)
+	observer := &listingObserver{scheduler: scheduler, ch: make(chan []string, 10)}
+	task := func(context.Context) error { return nil }
+	_ = scheduler.Add("a", new(Timer).SetObserver(observer), time.Hour, task)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = scheduler.Run(ctx) }()
+
+	// Observerからスケジューラを操作しても止まらない
+	var got [][]string
+	receive := func(n int) {
+		for i := 0; i < n; i++ {
+			select {
+			case jobs := <-observer.ch:
+				got = append(got, jobs)
+			case <-time.After(time.Second):
+				t.Fatalf("%s error\nobserver is blocked\n", t.Name())
+			}
+		}
+	}
+	receive(1)
+	_ = scheduler.Add("b", new(Timer).SetObserver(observer), time.Hour, task)
+	receive(1)
+	clock.Set(time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local))
+	receive(2)
+	_ = scheduler.Remove("b")
+	receive(1)
+
+	want := [][]string{{"a"}, {"a", "b"}, {"a", "b"}, {"a", "b"}, {"a"}}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Scheduler_SetLogger(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	scheduler := NewScheduler().SetLogger(testLogger(&buf))
+	timer := new(Timer)
+	_ = scheduler.Add("job", timer, time.Minute, func(context.Context) error { return nil })
+	_ = scheduler.Remove("job")
+	want := `level=INFO msg="timer started" job=job interval=1m0s` + "\n" +
+		`level=INFO msg="job added" job=job` + "\n" +
+		`level=INFO msg="timer stopped" job=job` + "\n" +
+		`level=INFO msg="job removed" job=job` + "\n"
+	got := buf.String()
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
@@ -1,32 +1,320 @@
 package gotimer
 
-import "time"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	TermInvalidFormatError = errors.New("invalid term format")
+)
 
 // NewTerm - 新しい期間を返す
 func NewTerm(start, stop Time) Term {
 	return Term{start: start, stop: stop}
 }
 
+// NewWeekdayTerm - 指定した曜日に開始する新しい期間を返す
+//   曜日を指定しなければ毎日開始する
+func NewWeekdayTerm(start, stop Time, weekdays ...time.Weekday) Term {
+	return Term{start: start, stop: stop, weekdays: newWeekdays(weekdays)}
+}
+
+// NewPreciseTerm - 秒未満まで見る新しい期間を返す
+//   秒で指定した期間と違い、判定する日時の秒未満を切り捨てない
+func NewPreciseTerm(start, stop PreciseTime) Term {
+	return NewPreciseWeekdayTerm(start, stop)
+}
+
+// NewPreciseWeekdayTerm - 指定した曜日に開始する、秒未満まで見る新しい期間を返す
+//   曜日を指定しなければ毎日開始する
+func NewPreciseWeekdayTerm(start, stop PreciseTime, weekdays ...time.Weekday) Term {
+	return Term{
+		start:     start.Time(),
+		stop:      stop.Time(),
+		startNsec: start.nanosecond(),
+		stopNsec:  stop.nanosecond(),
+		precise:   true,
+		weekdays:  newWeekdays(weekdays),
+	}
+}
+
 // Term - 期間の設定
 type Term struct {
-	start Time
-	stop  Time
+	start     Time
+	stop      Time
+	startNsec int      // startの秒未満 秒未満まで見る期間だけ使う
+	stopNsec  int      // stopの秒未満 秒未満まで見る期間だけ使う
+	precise   bool     // 秒未満まで見る期間か
+	weekdays  weekdays // 開始する曜日 ゼロ値なら毎日
+}
+
+// weekdays - 曜日の集合をビットで持つ型
+type weekdays uint8
+
+// newWeekdays - 曜日の集合を返す
+func newWeekdays(days []time.Weekday) weekdays {
+	var w weekdays
+	for _, d := range days {
+		w |= 1 << uint(d)
+	}
+	return w
+}
+
+// has - 曜日が含まれているか ゼロ値ならすべての曜日を含む
+func (w weekdays) has(weekday time.Weekday) bool {
+	return w == 0 || w&(1<<uint(weekday)) != 0
+}
+
+// weekdayNames - 曜日の略称 Term.Stringで使い、ParseTermでは大文字小文字を区別しない
+var weekdayNames = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// parseWeekday - 曜日の略称か英語名を曜日にする
+func parseWeekday(s string) (time.Weekday, bool) {
+	for w := time.Sunday; w <= time.Saturday; w++ {
+		if strings.EqualFold(s, weekdayNames[w]) || strings.EqualFold(s, w.String()) {
+			return w, true
+		}
+	}
+	return 0, false
+}
+
+// Weekdays - 開始する曜日を返す 毎日ならnilを返す
+func (t *Term) Weekdays() []time.Weekday {
+	if t.weekdays == 0 {
+		return nil
+	}
+	var res []time.Weekday
+	for w := time.Sunday; w <= time.Saturday; w++ {
+		if t.weekdays.has(w) {
+			res = append(res, w)
+		}
+	}
+	return res
+}
+
+// ParseTerm - "09:00-11:30"の形式の文字列を期間にする
+//   時刻はParseTimeと同じ形式で、秒未満を含む時刻があれば秒未満まで見る期間にする
+//   "09:00-11:30 [Mon,Tue]"や"09:00-11:30 [Mon-Fri]"のように、後ろに開始する曜日を付けられる
+func ParseTerm(s string) (Term, error) {
+	str := strings.TrimSpace(s)
+	var days []time.Weekday
+	if i := strings.Index(str, "["); i >= 0 {
+		if !strings.HasSuffix(str, "]") {
+			return Term{}, fmt.Errorf("%w: %q: weekdays must be closed with ]", TermInvalidFormatError, s)
+		}
+		var err error
+		if days, err = parseWeekdays(str[i+1 : len(str)-1]); err != nil {
+			return Term{}, fmt.Errorf("%w: %q: %s", TermInvalidFormatError, s, err)
+		}
+		if len(days) == 0 {
+			return Term{}, fmt.Errorf("%w: %q: empty weekdays", TermInvalidFormatError, s)
+		}
+		str = strings.TrimSpace(str[:i])
+	}
+
+	parts := strings.Split(str, "-")
+	if len(parts) != 2 {
+		return Term{}, fmt.Errorf("%w: %q: expected start-stop", TermInvalidFormatError, s)
+	}
+	start, err := ParsePreciseTime(parts[0])
+	if err != nil {
+		return Term{}, fmt.Errorf("%w: %q: start: %s", TermInvalidFormatError, s, err)
+	}
+	stop, err := ParsePreciseTime(parts[1])
+	if err != nil {
+		return Term{}, fmt.Errorf("%w: %q: stop: %s", TermInvalidFormatError, s, err)
+	}
+	if hasFraction(parts[0]) || hasFraction(parts[1]) {
+		return NewPreciseWeekdayTerm(start, stop, days...), nil
+	}
+	return NewWeekdayTerm(start.Time(), stop.Time(), days...), nil
+}
+
+// parseWeekdays - "Mon,Tue"や"Mon-Fri"の形式の文字列を曜日にする
+func parseWeekdays(s string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		names := strings.Split(part, "-")
+		if len(names) > 2 {
+			return nil, fmt.Errorf("invalid weekday range %q", part)
+		}
+		from, ok := parseWeekday(strings.TrimSpace(names[0]))
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", names[0])
+		}
+		to := from
+		if len(names) == 2 {
+			if to, ok = parseWeekday(strings.TrimSpace(names[1])); !ok {
+				return nil, fmt.Errorf("invalid weekday %q", names[1])
+			}
+		}
+		for w := from; ; w = (w + 1) % 7 { // Fri-Monのように週をまたいでもよい
+			days = append(days, w)
+			if w == to {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// String - "09:00:00-11:30:00"の形式の文字列を返す
+//   曜日の指定があれば後ろに"[Mon,Tue]"の形式で付け、秒未満まで見る期間なら秒未満も付ける
+func (t Term) String() string {
+	var s string
+	if t.precise {
+		s = t.startTime().String() + "-" + t.stopTime().String()
+	} else {
+		s = t.start.String() + "-" + t.stop.String()
+	}
+	if days := t.Weekdays(); len(days) > 0 {
+		names := make([]string, len(days))
+		for i, d := range days {
+			names[i] = weekdayNames[d]
+		}
+		s += " [" + strings.Join(names, ",") + "]"
+	}
+	return s
+}
+
+// MarshalText - Term.Stringと同じ形式にする
+func (t Term) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText - ParseTermと同じ形式の文字列から戻す
+func (t *Term) UnmarshalText(text []byte) error {
+	term, err := ParseTerm(string(text))
+	if err != nil {
+		return err
+	}
+	*t = term
+	return nil
+}
+
+// termJSON - TermのJSONでの形
+type termJSON struct {
+	Start    string   `json:"start"`
+	Stop     string   `json:"stop"`
+	Weekdays []string `json:"weekdays,omitempty"`
+}
+
+// MarshalJSON - {"start":"09:00:00","stop":"15:00:00","weekdays":["Mon"]}の形式にする
+//   曜日の指定がなければweekdaysは付けず、秒未満まで見る期間なら秒未満も付ける
+func (t Term) MarshalJSON() ([]byte, error) {
+	v := termJSON{Start: t.start.String(), Stop: t.stop.String()}
+	if t.precise {
+		v.Start, v.Stop = t.startTime().String(), t.stopTime().String()
+	}
+	for _, d := range t.Weekdays() {
+		v.Weekdays = append(v.Weekdays, weekdayNames[d])
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON - MarshalJSONの形式か、ParseTermと同じ形式の文字列から戻す
+//   nullなら変えない
+func (t *Term) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return t.UnmarshalText([]byte(s))
+	}
+
+	var v termJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Start == "" || v.Stop == "" {
+		return fmt.Errorf("%w: %s: start and stop are required", TermInvalidFormatError, data)
+	}
+	s := v.Start + "-" + v.Stop
+	if len(v.Weekdays) > 0 {
+		s += " [" + strings.Join(v.Weekdays, ",") + "]"
+	}
+	return t.UnmarshalText([]byte(s))
+}
+
+// Start - 開始時刻を返す 秒未満まで見る期間なら秒未満は切り捨てる
+func (t *Term) Start() Time {
+	return t.start
+}
+
+// Stop - 停止時刻を返す 秒未満まで見る期間なら秒未満は切り捨てる
+func (t *Term) Stop() Time {
+	return t.stop
+}
+
+// PreciseStart - 秒未満も含めた開始時刻を返す
+func (t *Term) PreciseStart() PreciseTime {
+	return t.startTime()
+}
+
+// PreciseStop - 秒未満も含めた停止時刻を返す
+func (t *Term) PreciseStop() PreciseTime {
+	return t.stopTime()
+}
+
+// IsPrecise - 秒未満まで見る期間か
+func (t *Term) IsPrecise() bool {
+	return t.precise
 }
 
 // runnable - startとstopの間にnowがあれば実行可能
+//   曜日の指定があれば、startの曜日が含まれている場合だけ実行可能
 func (t *Term) runnable(now time.Time) bool {
-	n := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, now.Location())
-	start := time.Date(now.Year(), now.Month(), now.Day(), t.start.hour(), t.start.minute(), t.start.second(), 0, now.Location())
-	stop := time.Date(now.Year(), now.Month(), now.Day(), t.stop.hour(), t.stop.minute(), t.stop.second(), 0, now.Location())
-	if t.stop < t.start {
-		nt := NewTime(now.Hour(), now.Minute(), now.Second())
-		if nt <= t.stop { // now <= stop なら、startを前日にする
-			start = start.AddDate(0, 0, -1)
+	n := now
+	if !t.precise {
+		n = now.Truncate(time.Second)
+	}
+	start, stop := t.boundary(now)
+	return t.weekdays.has(start.Weekday()) && !n.Before(start) && !n.After(stop)
+}
+
+// boundary - nowを含みうる開始日時と停止日時をnowのロケーションで返す
+//   日をまたぐ期間なら、nowがstop以前なら開始を前日に、そうでなければ停止を翌日にする
+//   夏時間の終了で2回ある時刻は、開始は1回目、停止は2回目として扱う
+func (t *Term) boundary(now time.Time) (time.Time, time.Time) {
+	startDay, stopDay := now.Day(), now.Day()
+	if t.stopTime() < t.startTime() {
+		nsec := 0
+		if t.precise {
+			nsec = now.Nanosecond()
+		}
+		nt := NewPreciseTime(now.Hour(), now.Minute(), now.Second(), nsec)
+		if nt <= t.stopTime() { // now <= stop なら、startを前日にする
+			startDay--
 		} else {
-			stop = stop.AddDate(0, 0, 1)
+			stopDay++
 		}
 	}
-	return !n.Before(start) && !n.After(stop)
+	start := localDate(now.Year(), now.Month(), startDay, t.start, false, now.Location()).Add(time.Duration(t.startNsec))
+	stop := localDate(now.Year(), now.Month(), stopDay, t.stop, true, now.Location()).Add(time.Duration(t.stopNsec))
+	return start, stop
+}
+
+// startTime - 秒未満も含めた開始時刻を返す
+func (t *Term) startTime() PreciseTime {
+	return t.start.precise(t.startNsec)
+}
+
+// stopTime - 秒未満も含めた停止時刻を返す
+func (t *Term) stopTime() PreciseTime {
+	return t.stop.precise(t.stopNsec)
 }
 
 // runnableSecond - 実行可能期間を秒で返す
@@ -39,9 +327,12 @@ func (t *Term) runnableSecond() int {
 }
 
 func (t *Term) Equal(term Term) bool {
-	return t.start == term.start && t.stop == term.stop
+	return t.start == term.start && t.stop == term.stop && t.weekdays == term.weekdays &&
+		t.startNsec == term.startNsec && t.stopNsec == term.stopNsec && t.precise == term.precise
 }
 
+// In - timeが期間内にあるか 日付を持たないので曜日の指定は見ない
+//   timeは秒までなので、期間の秒未満は見ない
 func (t *Term) In(time Time) bool {
 	if t.stop < t.start { // stop < start
 		return time <= t.stop || t.start <= time // start <= time || time <= stop
@@ -49,3 +340,15 @@ func (t *Term) In(time Time) bool {
 		return t.start <= time && time <= t.stop // start <= time <= stop
 	}
 }
+
+// InWeekday - weekdayのtimeが期間内にあるか
+//   日をまたぐ期間でtimeがstop以前なら、前日の曜日で判定する
+func (t *Term) InWeekday(weekday time.Weekday, time Time) bool {
+	if !t.In(time) {
+		return false
+	}
+	if t.stop < t.start && time <= t.stop {
+		weekday = (weekday + 6) % 7
+	}
+	return t.weekdays.has(weekday)
+}
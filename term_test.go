@@ -1,6 +1,8 @@
 package gotimer
 
 import (
+	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -15,57 +17,73 @@ func Test_Term_runnable(t *testing.T) {
 		want bool
 	}{
 		{name: "start == stopで、now < startならfalse",
-			term: Term{Time(9 * 60 * 60), Time(9 * 60 * 60)},
+			term: Term{start: Time(9 * 60 * 60), stop: Time(9 * 60 * 60)},
 			now:  time.Date(2020, 12, 25, 8, 59, 59, 0, time.Local),
 			want: false},
 		{name: "start == stopで、now == startならtrue",
-			term: Term{Time(9 * 60 * 60), Time(9 * 60 * 60)},
+			term: Term{start: Time(9 * 60 * 60), stop: Time(9 * 60 * 60)},
 			now:  time.Date(2020, 12, 25, 9, 0, 0, 0, time.Local),
 			want: true},
 		{name: "start == stopで、stop < nowならfalse",
-			term: Term{Time(9 * 60 * 60), Time(9 * 60 * 60)},
+			term: Term{start: Time(9 * 60 * 60), stop: Time(9 * 60 * 60)},
 			now:  time.Date(2020, 12, 25, 9, 0, 1, 0, time.Local),
 			want: false},
 		{name: "start < stopで、now < startならfalse",
-			term: Term{Time(9 * 60 * 60), Time(11*60*60 + 30*60)},
+			term: Term{start: Time(9 * 60 * 60), stop: Time(11*60*60 + 30*60)},
 			now:  time.Date(2020, 12, 25, 8, 59, 59, 0, time.Local),
 			want: false},
 		{name: "start < stopで、now == startならtrue",
-			term: Term{Time(9 * 60 * 60), Time(11*60*60 + 30*60)},
+			term: Term{start: Time(9 * 60 * 60), stop: Time(11*60*60 + 30*60)},
 			now:  time.Date(2020, 12, 25, 9, 0, 0, 0, time.Local),
 			want: true},
 		{name: "start < stopで、start < now < stopならtrue",
-			term: Term{Time(9 * 60 * 60), Time(11*60*60 + 30*60)},
+			term: Term{start: Time(9 * 60 * 60), stop: Time(11*60*60 + 30*60)},
 			now:  time.Date(2020, 12, 25, 10, 15, 0, 0, time.Local),
 			want: true},
 		{name: "start < stopで、now == stopならtrue",
-			term: Term{Time(9 * 60 * 60), Time(11*60*60 + 30*60)},
+			term: Term{start: Time(9 * 60 * 60), stop: Time(11*60*60 + 30*60)},
 			now:  time.Date(2020, 12, 25, 11, 30, 0, 0, time.Local),
 			want: true},
 		{name: "start < stopで、stop < nowならfalse",
-			term: Term{Time(9 * 60 * 60), Time(11*60*60 + 30*60)},
+			term: Term{start: Time(9 * 60 * 60), stop: Time(11*60*60 + 30*60)},
 			now:  time.Date(2020, 12, 25, 11, 30, 1, 0, time.Local),
 			want: false},
 		{name: "stop < startで、stop < now < startならfalse",
-			term: Term{Time(16*60*60 + 30*60), Time(5*60*60 + 24*60 + 59)},
+			term: Term{start: Time(16*60*60 + 30*60), stop: Time(5*60*60 + 24*60 + 59)},
 			now:  time.Date(2020, 12, 25, 16, 29, 59, 0, time.Local),
 			want: false},
 		{name: "stop < startで、now == startならtrue",
-			term: Term{Time(16*60*60 + 30*60), Time(5*60*60 + 24*60 + 59)},
+			term: Term{start: Time(16*60*60 + 30*60), stop: Time(5*60*60 + 24*60 + 59)},
 			now:  time.Date(2020, 12, 25, 16, 30, 0, 0, time.Local),
 			want: true},
 		{name: "stop < startで、start < now < 24:00:00ならtrue",
-			term: Term{Time(16*60*60 + 30*60), Time(5*60*60 + 24*60 + 59)},
+			term: Term{start: Time(16*60*60 + 30*60), stop: Time(5*60*60 + 24*60 + 59)},
 			now:  time.Date(2020, 12, 25, 23, 59, 59, 0, time.Local),
 			want: true},
 		{name: "stop < startで、00:00:00 < now < stopならtrue",
-			term: Term{Time(16*60*60 + 30*60), Time(5*60*60 + 24*60 + 59)},
+			term: Term{start: Time(16*60*60 + 30*60), stop: Time(5*60*60 + 24*60 + 59)},
 			now:  time.Date(2020, 12, 26, 0, 0, 1, 0, time.Local),
 			want: true},
 		{name: "stop < startで、now == stopならtrue",
-			term: Term{Time(16*60*60 + 30*60), Time(5*60*60 + 24*60 + 59)},
+			term: Term{start: Time(16*60*60 + 30*60), stop: Time(5*60*60 + 24*60 + 59)},
 			now:  time.Date(2020, 12, 26, 5, 24, 59, 0, time.Local),
 			want: true},
+		{name: "曜日の指定があり、startの曜日が含まれていればtrue",
+			term: NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Monday, time.Friday),
+			now:  time.Date(2020, 12, 25, 10, 0, 0, 0, time.Local),
+			want: true},
+		{name: "曜日の指定があり、startの曜日が含まれていなければfalse",
+			term: NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Monday, time.Friday),
+			now:  time.Date(2020, 12, 26, 10, 0, 0, 0, time.Local),
+			want: false},
+		{name: "stop < startで、翌日のstopまでは前日の曜日で判定するのでtrue",
+			term: NewWeekdayTerm(NewTime(16, 30, 0), NewTime(5, 30, 0), time.Friday),
+			now:  time.Date(2020, 12, 26, 5, 0, 0, 0, time.Local),
+			want: true},
+		{name: "stop < startで、当日のstart以降は当日の曜日で判定するのでfalse",
+			term: NewWeekdayTerm(NewTime(16, 30, 0), NewTime(5, 30, 0), time.Friday),
+			now:  time.Date(2020, 12, 26, 17, 0, 0, 0, time.Local),
+			want: false},
 	}
 
 	for _, test := range tests {
@@ -88,17 +106,20 @@ func Test_Term_Equal(t *testing.T) {
 		want bool
 	}{
 		{name: "startとstopが一致しているならtrue", want: true,
-			a: Term{Time(9 * 60 * 60), Time(15 * 60 * 60)},
-			b: Term{Time(9 * 60 * 60), Time(15 * 60 * 60)}},
+			a: Term{start: Time(9 * 60 * 60), stop: Time(15 * 60 * 60)},
+			b: Term{start: Time(9 * 60 * 60), stop: Time(15 * 60 * 60)}},
 		{name: "startだけが一致しているならfalse", want: false,
-			a: Term{Time(9 * 60 * 60), Time(15 * 60 * 60)},
-			b: Term{Time(9 * 60 * 60), Time(11*60*60 + 30*60)}},
+			a: Term{start: Time(9 * 60 * 60), stop: Time(15 * 60 * 60)},
+			b: Term{start: Time(9 * 60 * 60), stop: Time(11*60*60 + 30*60)}},
 		{name: "stopだけが一致しているならfalse", want: false,
-			a: Term{Time(9 * 60 * 60), Time(15 * 60 * 60)},
-			b: Term{Time(12*60*60 + 30*60), Time(15 * 60 * 60)}},
+			a: Term{start: Time(9 * 60 * 60), stop: Time(15 * 60 * 60)},
+			b: Term{start: Time(12*60*60 + 30*60), stop: Time(15 * 60 * 60)}},
 		{name: "両方一致していないならfalse", want: false,
-			a: Term{Time(9 * 60 * 60), Time(11*60*60 + 30*60)},
-			b: Term{Time(12*60*60 + 30*60), Time(15 * 60 * 60)}},
+			a: Term{start: Time(9 * 60 * 60), stop: Time(11*60*60 + 30*60)},
+			b: Term{start: Time(12*60*60 + 30*60), stop: Time(15 * 60 * 60)}},
+		{name: "曜日だけが一致していないならfalse", want: false,
+			a: NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Monday),
+			b: NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Tuesday)},
 	}
 
 	for _, test := range tests {
@@ -122,43 +143,43 @@ func Test_Term_In(t *testing.T) {
 		want bool
 	}{
 		{name: "start == stopでtime < startならfalse", want: false,
-			term: Term{Time(8 * 60 * 60), Time(8 * 60 * 60)},
+			term: Term{start: Time(8 * 60 * 60), stop: Time(8 * 60 * 60)},
 			time: Time(7*60*60 + 59*60 + 59)},
 		{name: "start == stopでstart == time == stopならtrue", want: true,
-			term: Term{Time(8 * 60 * 60), Time(8 * 60 * 60)},
+			term: Term{start: Time(8 * 60 * 60), stop: Time(8 * 60 * 60)},
 			time: Time(8 * 60 * 60)},
 		{name: "start == stopでstop < timeならfalse", want: false,
-			term: Term{Time(8 * 60 * 60), Time(8 * 60 * 60)},
+			term: Term{start: Time(8 * 60 * 60), stop: Time(8 * 60 * 60)},
 			time: Time(8*60*60 + 1)},
 		{name: "start < stopでtime < stopならfalse", want: false,
-			term: Term{Time(8*60*60 + 45*60), Time(15*60*60 + 15*60)},
+			term: Term{start: Time(8*60*60 + 45*60), stop: Time(15*60*60 + 15*60)},
 			time: Time(8*60*60 + 44*60 + 59)},
 		{name: "start < stopでstart == timeならtrue", want: true,
-			term: Term{Time(8*60*60 + 45*60), Time(15*60*60 + 15*60)},
+			term: Term{start: Time(8*60*60 + 45*60), stop: Time(15*60*60 + 15*60)},
 			time: Time(8*60*60 + 45*60)},
 		{name: "start < stopでstart < time < stopならtrue", want: true,
-			term: Term{Time(8*60*60 + 45*60), Time(15*60*60 + 15*60)},
+			term: Term{start: Time(8*60*60 + 45*60), stop: Time(15*60*60 + 15*60)},
 			time: Time(9 * 60 * 60)},
 		{name: "start < stopでstop == timeならtrue", want: true,
-			term: Term{Time(8*60*60 + 45*60), Time(15*60*60 + 15*60)},
+			term: Term{start: Time(8*60*60 + 45*60), stop: Time(15*60*60 + 15*60)},
 			time: Time(15*60*60 + 15*60)},
 		{name: "start < stopでstop < timeならfalse", want: false,
-			term: Term{Time(8*60*60 + 45*60), Time(15*60*60 + 15*60)},
+			term: Term{start: Time(8*60*60 + 45*60), stop: Time(15*60*60 + 15*60)},
 			time: Time(15*60*60 + 15*60 + 1)},
 		{name: "stop < startでstop < time < startならfalse", want: false,
-			term: Term{Time(16*60*60 + 30*60), Time(5*60*60 + 30*60)},
+			term: Term{start: Time(16*60*60 + 30*60), stop: Time(5*60*60 + 30*60)},
 			time: Time(16*60*60 + 29*60 + 59)},
 		{name: "stop < startでstart == timeならtrue", want: true,
-			term: Term{Time(16*60*60 + 30*60), Time(5*60*60 + 30*60)},
+			term: Term{start: Time(16*60*60 + 30*60), stop: Time(5*60*60 + 30*60)},
 			time: Time(16*60*60 + 30*60)},
 		{name: "stop < startでstart < timeならtrue", want: true,
-			term: Term{Time(16*60*60 + 30*60), Time(5*60*60 + 30*60)},
+			term: Term{start: Time(16*60*60 + 30*60), stop: Time(5*60*60 + 30*60)},
 			time: Time(23*60*60 + 59*60 + 59)},
 		{name: "stop < startでtime < stopならtrue", want: true,
-			term: Term{Time(16*60*60 + 30*60), Time(5*60*60 + 30*60)},
+			term: Term{start: Time(16*60*60 + 30*60), stop: Time(5*60*60 + 30*60)},
 			time: Time(0)},
 		{name: "stop < startでstop == timeならtrue", want: true,
-			term: Term{Time(16*60*60 + 30*60), Time(5*60*60 + 30*60)},
+			term: Term{start: Time(16*60*60 + 30*60), stop: Time(5*60*60 + 30*60)},
 			time: Time(5*60*60 + 30*60)},
 	}
 
@@ -214,3 +235,286 @@ func Test_Term_runnableSecond(t *testing.T) {
 		})
 	}
 }
+
+func Test_NewWeekdayTerm(t *testing.T) {
+	t.Parallel()
+	start := NewTime(9, 0, 0)
+	stop := NewTime(15, 0, 0)
+	want := Term{start: start, stop: stop, weekdays: 1<<uint(time.Monday) | 1<<uint(time.Saturday)}
+	got := NewWeekdayTerm(start, stop, time.Saturday, time.Monday, time.Saturday)
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Term_Weekdays(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		term Term
+		want []time.Weekday
+	}{
+		{name: "曜日の指定がなければnil", term: NewTerm(NewTime(9, 0, 0), NewTime(15, 0, 0)), want: nil},
+		{name: "曜日の指定があれば日曜から順に返す",
+			term: NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Friday, time.Monday),
+			want: []time.Weekday{time.Monday, time.Friday}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := test.term.Weekdays()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Term_InWeekday(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		term    Term
+		weekday time.Weekday
+		time    Time
+		want    bool
+	}{
+		{name: "期間外ならfalse", want: false,
+			term:    NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Monday),
+			weekday: time.Monday, time: NewTime(8, 0, 0)},
+		{name: "期間内で曜日が含まれていればtrue", want: true,
+			term:    NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Monday),
+			weekday: time.Monday, time: NewTime(10, 0, 0)},
+		{name: "期間内でも曜日が含まれていなければfalse", want: false,
+			term:    NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Monday),
+			weekday: time.Tuesday, time: NewTime(10, 0, 0)},
+		{name: "曜日の指定がなければ期間内ならtrue", want: true,
+			term:    NewTerm(NewTime(9, 0, 0), NewTime(15, 0, 0)),
+			weekday: time.Sunday, time: NewTime(10, 0, 0)},
+		{name: "stop < startでtime <= stopなら前日の曜日で判定する", want: true,
+			term:    NewWeekdayTerm(NewTime(16, 30, 0), NewTime(5, 30, 0), time.Saturday),
+			weekday: time.Sunday, time: NewTime(1, 0, 0)},
+		{name: "stop < startで日曜のtime <= stopなら土曜で判定する", want: false,
+			term:    NewWeekdayTerm(NewTime(16, 30, 0), NewTime(5, 30, 0), time.Sunday),
+			weekday: time.Sunday, time: NewTime(1, 0, 0)},
+		{name: "stop < startでstart <= timeなら当日の曜日で判定する", want: true,
+			term:    NewWeekdayTerm(NewTime(16, 30, 0), NewTime(5, 30, 0), time.Sunday),
+			weekday: time.Sunday, time: NewTime(17, 0, 0)},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := test.term.InWeekday(test.weekday, test.time)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Term_runnable_Precise(t *testing.T) {
+	t.Parallel()
+	open := NewPreciseTerm(NewPreciseTime(8, 59, 59, 500_000_000), NewPreciseTime(9, 0, 0, 250_000_000))
+	overnight := NewPreciseTerm(NewPreciseTime(23, 59, 59, 500_000_000), NewPreciseTime(0, 0, 0, 250_000_000))
+	tests := []struct {
+		name string
+		term Term
+		now  time.Time
+		want bool
+	}{
+		{name: "開始の直前ならfalse", term: open, now: time.Date(2021, 1, 4, 8, 59, 59, 499_999_999, time.Local), want: false},
+		{name: "開始ちょうどならtrue", term: open, now: time.Date(2021, 1, 4, 8, 59, 59, 500_000_000, time.Local), want: true},
+		{name: "秒をまたいでもtrue", term: open, now: time.Date(2021, 1, 4, 9, 0, 0, 100_000_000, time.Local), want: true},
+		{name: "停止ちょうどならtrue", term: open, now: time.Date(2021, 1, 4, 9, 0, 0, 250_000_000, time.Local), want: true},
+		{name: "停止の直後ならfalse", term: open, now: time.Date(2021, 1, 4, 9, 0, 0, 250_000_001, time.Local), want: false},
+		{name: "秒で指定した期間は秒未満を切り捨てる",
+			term: NewTerm(NewTime(9, 0, 0), NewTime(9, 0, 0)), now: time.Date(2021, 1, 4, 9, 0, 0, 999_999_999, time.Local), want: true},
+		{name: "日をまたぐ期間で、日をまたぐ前ならtrue", term: overnight, now: time.Date(2021, 1, 4, 23, 59, 59, 600_000_000, time.Local), want: true},
+		{name: "日をまたぐ期間で、日をまたいだ後ならtrue", term: overnight, now: time.Date(2021, 1, 5, 0, 0, 0, 200_000_000, time.Local), want: true},
+		{name: "日をまたぐ期間で、停止の後ならfalse", term: overnight, now: time.Date(2021, 1, 5, 0, 0, 0, 300_000_000, time.Local), want: false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := test.term.runnable(test.now)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_NewPreciseWeekdayTerm(t *testing.T) {
+	t.Parallel()
+	want := Term{start: NewTime(8, 59, 59), stop: NewTime(9, 0, 0), startNsec: 500_000_000, stopNsec: 250_000_000, precise: true,
+		weekdays: 1<<time.Monday | 1<<time.Friday}
+	got := NewPreciseWeekdayTerm(NewPreciseTime(8, 59, 59, 500_000_000), NewPreciseTime(9, 0, 0, 250_000_000), time.Monday, time.Friday)
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_ParseTerm(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		s       string
+		want    Term
+		wantErr bool
+	}{
+		{name: "開始と停止", s: "09:00-11:30", want: NewTerm(NewTime(9, 0, 0), NewTime(11, 30, 0))},
+		{name: "秒と空白", s: " 09:00:00 - 11:30:15 ", want: NewTerm(NewTime(9, 0, 0), NewTime(11, 30, 15))},
+		{name: "曜日の指定", s: "09:00-11:30 [Mon,tue]", want: NewWeekdayTerm(NewTime(9, 0, 0), NewTime(11, 30, 0), time.Monday, time.Tuesday)},
+		{name: "曜日の範囲", s: "09:00-11:30 [Mon-Fri]",
+			want: NewWeekdayTerm(NewTime(9, 0, 0), NewTime(11, 30, 0), time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)},
+		{name: "週をまたぐ曜日の範囲と英語名", s: "22:00-02:00 [Saturday-Sun]",
+			want: NewWeekdayTerm(NewTime(22, 0, 0), NewTime(2, 0, 0), time.Saturday, time.Sunday)},
+		{name: "秒未満があれば秒未満まで見る期間", s: "08:59:59.5-09:00:00.25",
+			want: NewPreciseTerm(NewPreciseTime(8, 59, 59, 500_000_000), NewPreciseTime(9, 0, 0, 250_000_000))},
+		{name: "区切りがなければerror", s: "09:00", wantErr: true},
+		{name: "時刻が不正ならerror", s: "09:00-25:00", wantErr: true},
+		{name: "曜日が不正ならerror", s: "09:00-11:30 [Mon,Foo]", wantErr: true},
+		{name: "曜日が空ならerror", s: "09:00-11:30 []", wantErr: true},
+		{name: "曜日が閉じていなければerror", s: "09:00-11:30 [Mon", wantErr: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseTerm(test.s)
+			if !reflect.DeepEqual(test.want, got) || test.wantErr != errors.Is(err, TermInvalidFormatError) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.want, test.wantErr, got, err)
+			}
+		})
+	}
+}
+
+func Test_Term_String(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		term Term
+		want string
+	}{
+		{name: "開始と停止", term: NewTerm(NewTime(9, 0, 0), NewTime(11, 30, 0)), want: "09:00:00-11:30:00"},
+		{name: "曜日の指定", term: NewWeekdayTerm(NewTime(9, 0, 0), NewTime(11, 30, 0), time.Friday, time.Monday), want: "09:00:00-11:30:00 [Mon,Fri]"},
+		{name: "秒未満まで見る期間", term: NewPreciseTerm(NewPreciseTime(8, 59, 59, 500_000_000), NewPreciseTime(9, 0, 0, 0)),
+			want: "08:59:59.500-09:00:00.000"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := test.term.String()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+			if parsed, err := ParseTerm(got); err != nil || !parsed.Equal(test.term) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v, %+v\n", t.Name(), test.term, parsed, err)
+			}
+		})
+	}
+}
+
+func Test_Term_Start_Stop(t *testing.T) {
+	t.Parallel()
+	term := NewPreciseTerm(NewPreciseTime(8, 59, 59, 500_000_000), NewPreciseTime(9, 0, 0, 250_000_000))
+	want := []interface{}{NewTime(8, 59, 59), NewTime(9, 0, 0),
+		NewPreciseTime(8, 59, 59, 500_000_000), NewPreciseTime(9, 0, 0, 250_000_000), true}
+	got := []interface{}{term.Start(), term.Stop(), term.PreciseStart(), term.PreciseStop(), term.IsPrecise()}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Term_MarshalJSON(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		term Term
+		want string
+	}{
+		{name: "曜日の指定がなければweekdaysを付けない", term: NewTerm(NewTime(9, 0, 0), NewTime(15, 0, 0)),
+			want: `{"start":"09:00:00","stop":"15:00:00"}`},
+		{name: "曜日の指定", term: NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Monday, time.Tuesday),
+			want: `{"start":"09:00:00","stop":"15:00:00","weekdays":["Mon","Tue"]}`},
+		{name: "秒未満まで見る期間", term: NewPreciseTerm(NewPreciseTime(8, 59, 59, 500_000_000), NewPreciseTime(9, 0, 0, 0)),
+			want: `{"start":"08:59:59.500","stop":"09:00:00.000"}`},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			b, err := json.Marshal(test.term)
+			if err != nil || test.want != string(b) {
+				t.Errorf("%s error\nwant: %+v\ngot: %s, %+v\n", t.Name(), test.want, b, err)
+			}
+			var got Term
+			if err := json.Unmarshal(b, &got); err != nil || !got.Equal(test.term) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v, %+v\n", t.Name(), test.term, got, err)
+			}
+		})
+	}
+}
+
+func Test_Term_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		data    string
+		want    Term
+		wantErr bool
+	}{
+		{name: "文字列の形式", data: `"09:00-15:00 [Mon-Fri]"`,
+			want: NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)},
+		{name: "オブジェクトの形式", data: `{"start":"09:00","stop":"15:00","weekdays":["sat","sun"]}`,
+			want: NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Saturday, time.Sunday)},
+		{name: "stopがなければerror", data: `{"start":"09:00"}`, wantErr: true},
+		{name: "時刻が不正ならerror", data: `{"start":"09:00","stop":"24:00"}`, wantErr: true},
+		{name: "曜日が不正ならerror", data: `{"start":"09:00","stop":"15:00","weekdays":["foo"]}`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var got Term
+			err := json.Unmarshal([]byte(test.data), &got)
+			if !reflect.DeepEqual(test.want, got) || test.wantErr != errors.Is(err, TermInvalidFormatError) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.want, test.wantErr, got, err)
+			}
+		})
+	}
+}
+
+func Test_Term_UnmarshalJSON_Null(t *testing.T) {
+	t.Parallel()
+	want := NewTerm(NewTime(9, 0, 0), NewTime(15, 0, 0))
+	got := want
+	if err := json.Unmarshal([]byte(`null`), &got); err != nil || !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v, %+v\n", t.Name(), want, got, err)
+	}
+}
+
+func Test_Term_MarshalText(t *testing.T) {
+	t.Parallel()
+	want := NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Monday)
+	b, err := want.MarshalText()
+	if err != nil || string(b) != "09:00:00-15:00:00 [Mon]" {
+		t.Fatalf("%s error\ngot: %s, %+v\n", t.Name(), b, err)
+	}
+	var got Term
+	if err := got.UnmarshalText(b); err != nil || !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v, %+v\n", t.Name(), want, got, err)
+	}
+}
@@ -1,6 +1,21 @@
 package gotimer
 
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	TimeInvalidFormatError = errors.New("invalid time format")
+)
+
 // NewTime - 新しいgotimer.Timeを生成する
+//   範囲外の値は24時間で丸められるので、文字列から作るならParseTimeを使う
 func NewTime(hour, minute, second int) Time {
 	sec := (hour*60*60 + minute*60 + second) % (24 * 60 * 60)
 	if sec < 0 {
@@ -24,3 +39,215 @@ func (t Time) minute() int {
 func (t Time) second() int {
 	return int(t) % 60
 }
+
+// ParseTime - "15:04:05"か"15:04"の形式の文字列をTimeにする
+//   時分秒はそれぞれ2桁で、範囲外の値は丸めずにerrorを返す
+func ParseTime(s string) (Time, error) {
+	pt, err := parseTime(s, false)
+	if err != nil {
+		return 0, err
+	}
+	return pt.Time(), nil
+}
+
+// String - "15:04:05"の形式の文字列を返す
+func (t Time) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d", t.hour(), t.minute(), t.second())
+}
+
+// MarshalText - "15:04:05"の形式にする
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText - ParseTimeと同じ形式の文字列から戻す
+func (t *Time) UnmarshalText(text []byte) error {
+	v, err := ParseTime(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// UnmarshalJSON - UnmarshalTextと同じ形式の文字列か、MarshalTextを実装する前の形式の秒の数値から戻す
+//   秒の数値は0から86399までを受け付ける
+func (t *Time) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case bytes.HasPrefix(data, []byte(`"`)):
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return t.UnmarshalText([]byte(s))
+	}
+
+	var sec int
+	if err := json.Unmarshal(data, &sec); err != nil {
+		return fmt.Errorf("%w: %s: expected a string or seconds", TimeInvalidFormatError, data)
+	}
+	if sec < 0 || time.Duration(sec)*time.Second >= oneDay {
+		return fmt.Errorf("%w: %s: seconds out of range 0-%d", TimeInvalidFormatError, data, int(oneDay/time.Second)-1)
+	}
+	*t = Time(sec)
+	return nil
+}
+
+// NewPreciseTime - 秒未満までの新しいgotimer.PreciseTimeを生成する
+func NewPreciseTime(hour, minute, second, nanosecond int) PreciseTime {
+	d := (time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+		time.Duration(second)*time.Second + time.Duration(nanosecond)) % oneDay
+	if d < 0 {
+		d = oneDay + d
+	}
+
+	return PreciseTime(d)
+}
+
+// oneDay - 1日の長さ
+const oneDay = 24 * time.Hour
+
+// PreciseTime - 時分秒と秒未満をナノ秒にした構造体
+type PreciseTime int64
+
+// Time - 秒未満を切り捨てたTimeを返す
+func (t PreciseTime) Time() Time {
+	return Time(time.Duration(t) / time.Second)
+}
+
+func (t PreciseTime) nanosecond() int {
+	return int(time.Duration(t) % time.Second)
+}
+
+// ParsePreciseTime - "15:04:05.000"か"15:04:05"か"15:04"の形式の文字列をPreciseTimeにする
+//   秒未満は9桁まで受け付け、範囲外の値は丸めずにerrorを返す
+func ParsePreciseTime(s string) (PreciseTime, error) {
+	return parseTime(s, true)
+}
+
+// String - "15:04:05.000"の形式の文字列を返す
+//   秒未満は、値を失わない範囲でミリ秒、マイクロ秒、ナノ秒の桁数にする
+func (t PreciseTime) String() string {
+	nsec := t.nanosecond()
+	switch {
+	case nsec%int(time.Millisecond) == 0:
+		return fmt.Sprintf("%s.%03d", t.Time(), nsec/int(time.Millisecond))
+	case nsec%int(time.Microsecond) == 0:
+		return fmt.Sprintf("%s.%06d", t.Time(), nsec/int(time.Microsecond))
+	}
+	return fmt.Sprintf("%s.%09d", t.Time(), nsec)
+}
+
+// MarshalText - "15:04:05.000"の形式にする
+func (t PreciseTime) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText - ParsePreciseTimeと同じ形式の文字列から戻す
+func (t *PreciseTime) UnmarshalText(text []byte) error {
+	v, err := ParsePreciseTime(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// hasFraction - 時刻の文字列に秒未満があるか
+func hasFraction(s string) bool {
+	return strings.Contains(s, ".")
+}
+
+// parseTime - 時刻の文字列をPreciseTimeにする
+//   fractionがfalseなら秒未満を受け付けない
+func parseTime(s string, fraction bool) (PreciseTime, error) {
+	str := strings.TrimSpace(s)
+	nsec := 0
+	if i := strings.Index(str, "."); i >= 0 {
+		digits := str[i+1:]
+		if !fraction {
+			return 0, fmt.Errorf("%w: %q: fractional seconds are not allowed", TimeInvalidFormatError, s)
+		}
+		if len(digits) == 0 || len(digits) > 9 || strings.Count(str, ":") != 2 {
+			return 0, fmt.Errorf("%w: %q: fractional seconds must follow seconds with 1 to 9 digits", TimeInvalidFormatError, s)
+		}
+		n, err := strconv.ParseUint(digits, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q: invalid fractional seconds %q", TimeInvalidFormatError, s, digits)
+		}
+		nsec = int(n) * pow10(9-len(digits))
+		str = str[:i]
+	}
+
+	parts := strings.Split(str, ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return 0, fmt.Errorf("%w: %q: expected hh:mm or hh:mm:ss", TimeInvalidFormatError, s)
+	}
+	fields := []struct {
+		name string
+		max  int
+	}{{name: "hour", max: 23}, {name: "minute", max: 59}, {name: "second", max: 59}}
+	values := make([]int, 3)
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil || len(part) == 0 || part[0] == '-' || part[0] == '+' {
+			return 0, fmt.Errorf("%w: %q: invalid %s %q", TimeInvalidFormatError, s, fields[i].name, part)
+		}
+		if len(part) != 2 {
+			return 0, fmt.Errorf("%w: %q: %s %q must be two digits", TimeInvalidFormatError, s, fields[i].name, part)
+		}
+		if v > fields[i].max {
+			return 0, fmt.Errorf("%w: %q: %s %d out of range 0-%d", TimeInvalidFormatError, s, fields[i].name, v, fields[i].max)
+		}
+		values[i] = v
+	}
+	return NewPreciseTime(values[0], values[1], values[2], nsec), nil
+}
+
+// pow10 - 10のn乗を返す
+func pow10(n int) int {
+	v := 1
+	for i := 0; i < n; i++ {
+		v *= 10
+	}
+	return v
+}
+
+// precise - 秒未満をnanosecondにしたPreciseTimeを返す
+func (t Time) precise(nanosecond int) PreciseTime {
+	return PreciseTime(time.Duration(t)*time.Second + time.Duration(nanosecond))
+}
+
+// localDate - 年月日とTimeから、locでの日時を返す
+//   夏時間の開始で飛ばされて存在しない時刻は、飛ばされた分だけ後ろにずらす (02:30 -> 03:30)
+//   夏時間の終了で2回ある時刻は、latestがfalseなら1回目、trueなら2回目を返す
+func localDate(year int, month time.Month, day int, t Time, latest bool, loc *time.Location) time.Time {
+	u := time.Date(year, month, day, t.hour(), t.minute(), t.second(), 0, time.UTC)
+	_, before := u.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := u.Add(24 * time.Hour).In(loc).Zone()
+	early := u.Add(-time.Duration(before) * time.Second).In(loc)
+	late := u.Add(-time.Duration(after) * time.Second).In(loc)
+	earlyValid := zoneOffset(early) == before
+	lateValid := zoneOffset(late) == after
+
+	switch {
+	case earlyValid && lateValid:
+		if latest {
+			return late
+		}
+		return early
+	case lateValid:
+		return late
+	default: // 存在しない時刻も、変更前のオフセットを使えば飛ばされた分だけ後ろになる
+		return early
+	}
+}
+
+// zoneOffset - UTCからのオフセットを秒で返す
+func zoneOffset(t time.Time) int {
+	_, offset := t.Zone()
+	return offset
+}
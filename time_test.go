@@ -1,8 +1,12 @@
 package gotimer
 
 import (
+	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
+	_ "time/tzdata"
 )
 
 func Test_Time_hour(t *testing.T) {
@@ -113,3 +117,270 @@ func Test_NewTime(t *testing.T) {
 		})
 	}
 }
+
+func Test_localDate(t *testing.T) {
+	t.Parallel()
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		day    time.Time
+		time   Time
+		latest bool
+		want   time.Time
+	}{
+		{name: "夏時間の切り替えがなければそのままの時刻を返す",
+			day:  time.Date(2021, 3, 13, 0, 0, 0, 0, newYork),
+			time: NewTime(2, 30, 0),
+			want: time.Date(2021, 3, 13, 7, 30, 0, 0, time.UTC)},
+		{name: "夏時間の開始で存在しない時刻は飛ばされた分だけ後ろにずらす",
+			day:  time.Date(2021, 3, 14, 0, 0, 0, 0, newYork),
+			time: NewTime(2, 30, 0),
+			want: time.Date(2021, 3, 14, 7, 30, 0, 0, time.UTC)},
+		{name: "夏時間の開始後の時刻は夏時間で返す",
+			day:  time.Date(2021, 3, 14, 0, 0, 0, 0, newYork),
+			time: NewTime(9, 0, 0),
+			want: time.Date(2021, 3, 14, 13, 0, 0, 0, time.UTC)},
+		{name: "夏時間の終了で2回ある時刻は、latestがfalseなら1回目を返す",
+			day:  time.Date(2021, 11, 7, 0, 0, 0, 0, newYork),
+			time: NewTime(1, 30, 0),
+			want: time.Date(2021, 11, 7, 5, 30, 0, 0, time.UTC)},
+		{name: "夏時間の終了で2回ある時刻は、latestがtrueなら2回目を返す",
+			day:    time.Date(2021, 11, 7, 0, 0, 0, 0, newYork),
+			time:   NewTime(1, 30, 0),
+			latest: true,
+			want:   time.Date(2021, 11, 7, 6, 30, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := localDate(test.day.Year(), test.day.Month(), test.day.Day(), test.time, test.latest, newYork)
+			if !test.want.Equal(got) || got.Location() != newYork {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_NewPreciseTime(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name                             string
+		hour, minute, second, nanosecond int
+		want                             PreciseTime
+	}{
+		{name: "0時ちょうど", want: 0},
+		{name: "秒未満まで持つ", hour: 8, minute: 59, second: 59, nanosecond: 500_000_000,
+			want: PreciseTime(8*time.Hour + 59*time.Minute + 59*time.Second + 500*time.Millisecond)},
+		{name: "24時以降は翌日の時刻になる", hour: 24, nanosecond: 1, want: 1},
+		{name: "負の値は前日の時刻になる", nanosecond: -1, want: PreciseTime(24*time.Hour - 1)},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := NewPreciseTime(test.hour, test.minute, test.second, test.nanosecond)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_PreciseTime_Time(t *testing.T) {
+	t.Parallel()
+	pt := NewPreciseTime(8, 59, 59, 500_000_000)
+	want := []interface{}{NewTime(8, 59, 59), 500_000_000}
+	got := []interface{}{pt.Time(), pt.nanosecond()}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_ParseTime(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		s       string
+		want    Time
+		wantErr bool
+	}{
+		{name: "時分秒", s: "15:04:05", want: NewTime(15, 4, 5)},
+		{name: "時分", s: "09:30", want: NewTime(9, 30, 0)},
+		{name: "1桁の時ならerror", s: "9:30", wantErr: true},
+		{name: "1桁の分ならerror", s: "09:5", wantErr: true},
+		{name: "1桁の秒ならerror", s: "09:05:1", wantErr: true},
+		{name: "3桁の時ならerror", s: "009:00", wantErr: true},
+		{name: "前後の空白は無視する", s: " 09:30 ", want: NewTime(9, 30, 0)},
+		{name: "時が範囲外ならerror", s: "24:00", wantErr: true},
+		{name: "分が範囲外ならerror", s: "09:60", wantErr: true},
+		{name: "秒が範囲外ならerror", s: "09:00:60", wantErr: true},
+		{name: "負の値ならerror", s: "-1:00", wantErr: true},
+		{name: "数値でなければerror", s: "ab:00", wantErr: true},
+		{name: "区切りが足りなければerror", s: "0930", wantErr: true},
+		{name: "区切りが多すぎればerror", s: "09:30:00:00", wantErr: true},
+		{name: "秒未満があればerror", s: "09:30:00.5", wantErr: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseTime(test.s)
+			if test.want != got || test.wantErr != errors.Is(err, TimeInvalidFormatError) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.want, test.wantErr, got, err)
+			}
+		})
+	}
+}
+
+func Test_ParseTime_ErrorMessage(t *testing.T) {
+	t.Parallel()
+	_, err := ParseTime("25:00")
+	want := `invalid time format: "25:00": hour 25 out of range 0-23`
+	if err == nil || want != err.Error() {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, err)
+	}
+}
+
+func Test_ParseTime_DigitsErrorMessage(t *testing.T) {
+	t.Parallel()
+	_, err := ParseTime("9:30")
+	want := `invalid time format: "9:30": hour "9" must be two digits`
+	if err == nil || want != err.Error() {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, err)
+	}
+}
+
+func Test_ParsePreciseTime(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		s       string
+		want    PreciseTime
+		wantErr bool
+	}{
+		{name: "秒未満がなければ秒まで", s: "08:59:59", want: NewPreciseTime(8, 59, 59, 0)},
+		{name: "ミリ秒", s: "08:59:59.5", want: NewPreciseTime(8, 59, 59, 500_000_000)},
+		{name: "ナノ秒", s: "08:59:59.000000001", want: NewPreciseTime(8, 59, 59, 1)},
+		{name: "10桁以上ならerror", s: "08:59:59.0000000001", wantErr: true},
+		{name: "秒がなければerror", s: "08:59.5", wantErr: true},
+		{name: "秒未満が空ならerror", s: "08:59:59.", wantErr: true},
+		{name: "秒未満が数値でなければerror", s: "08:59:59.a", wantErr: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParsePreciseTime(test.s)
+			if test.want != got || test.wantErr != errors.Is(err, TimeInvalidFormatError) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.want, test.wantErr, got, err)
+			}
+		})
+	}
+}
+
+func Test_Time_String(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		time Time
+		want string
+	}{
+		{name: "0時", time: NewTime(0, 0, 0), want: "00:00:00"},
+		{name: "2桁", time: NewTime(23, 59, 59), want: "23:59:59"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := test.time.String()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_PreciseTime_String(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		time PreciseTime
+		want string
+	}{
+		{name: "秒未満がなくてもミリ秒まで", time: NewPreciseTime(9, 0, 0, 0), want: "09:00:00.000"},
+		{name: "ミリ秒", time: NewPreciseTime(8, 59, 59, 500_000_000), want: "08:59:59.500"},
+		{name: "マイクロ秒", time: NewPreciseTime(8, 59, 59, 500_001_000), want: "08:59:59.500001"},
+		{name: "ナノ秒", time: NewPreciseTime(8, 59, 59, 1), want: "08:59:59.000000001"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := test.time.String()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Time_JSON(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Time    Time        `json:"time"`
+		Precise PreciseTime `json:"precise"`
+	}
+	want := config{Time: NewTime(9, 30, 0), Precise: NewPreciseTime(8, 59, 59, 500_000_000)}
+	b, err := json.Marshal(want)
+	if err != nil || string(b) != `{"time":"09:30:00","precise":"08:59:59.500"}` {
+		t.Fatalf("%s error\ngot: %s, %+v\n", t.Name(), b, err)
+	}
+	var got config
+	if err := json.Unmarshal(b, &got); err != nil || !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v, %+v\n", t.Name(), want, got, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"time":"25:00"}`), &got); !errors.Is(err, TimeInvalidFormatError) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), TimeInvalidFormatError, err)
+	}
+}
+
+func Test_Time_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		data    string
+		want    Time
+		wantErr error
+	}{
+		{name: "文字列から戻せる", data: `"09:30:00"`, want: NewTime(9, 30, 0)},
+		{name: "以前の形式の秒の数値からも戻せる", data: `34200`, want: NewTime(9, 30, 0)},
+		{name: "nullなら変えない", data: `null`, want: NewTime(1, 0, 0)},
+		{name: "不正な文字列ならerror", data: `"25:00"`, want: NewTime(1, 0, 0), wantErr: TimeInvalidFormatError},
+		{name: "範囲外の秒ならerror", data: `86400`, want: NewTime(1, 0, 0), wantErr: TimeInvalidFormatError},
+		{name: "負の秒ならerror", data: `-1`, want: NewTime(1, 0, 0), wantErr: TimeInvalidFormatError},
+		{name: "整数でない数値ならerror", data: `1.5`, want: NewTime(1, 0, 0), wantErr: TimeInvalidFormatError},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := NewTime(1, 0, 0)
+			err := json.Unmarshal([]byte(test.data), &got)
+			if !reflect.DeepEqual(test.want, got) || !errors.Is(err, test.wantErr) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.want, test.wantErr, got, err)
+			}
+		})
+	}
+}
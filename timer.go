@@ -3,6 +3,9 @@ package gotimer
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
 	"sort"
 	"sync"
 	"time"
@@ -13,25 +16,518 @@ var (
 	TimerNotSetIntervalError = errors.New("not set interval")
 	TimerNotSetTaskError     = errors.New("not set task")
 	TimerIsRunningError      = errors.New("timer is running now")
+	TimerTaskTimeoutError    = errors.New("task timeout")
+	TimerNoTermsError        = errors.New("no terms")
+	TimerTermNotFoundError   = errors.New("term not found")
+	TimerNotRunningError     = errors.New("timer is not running")
 )
 
+// OverlapPolicy - タスクの実行中に次の実行時刻が来たときの扱い
+type OverlapPolicy int
+
+const (
+	OverlapSkip     OverlapPolicy = iota // 実行しない
+	OverlapQueue                         // 実行中のタスクが終わったら、溜まった回数だけ続けて実行する
+	OverlapCoalesce                      // 実行中のタスクが終わったら、何回溜まっても1回だけ実行する
+	OverlapParallel                      // 並行して実行する
+)
+
+// SkipReason - 実行時刻が来てもタスクを実行しなかった理由
+type SkipReason int
+
+const (
+	SkipReasonOverlap   SkipReason = iota // 実行中のタスクが同時実行数の上限に達していた
+	SkipReasonCoalesced                   // 溜まっている実行にまとめた
+	SkipReasonLimiter                     // 共有しているLimiterの上限に達していた
+	SkipReasonPaused                      // 一時停止していた
+)
+
+func (r SkipReason) String() string {
+	switch r {
+	case SkipReasonOverlap:
+		return "overlap"
+	case SkipReasonCoalesced:
+		return "coalesced"
+	case SkipReasonLimiter:
+		return "limiter"
+	case SkipReasonPaused:
+		return "paused"
+	}
+	return "unknown"
+}
+
+// ResumePolicy - 再開したときの、一時停止中に来た実行時刻の扱い
+type ResumePolicy int
+
+const (
+	ResumeSkip    ResumePolicy = iota // 一時停止中の実行時刻は捨て、次の実行時刻を待つ
+	ResumeRunOnce                     // 一時停止中に実行時刻が来ていれば、再開時に1回だけ実行する
+	ResumeRealign                     // 再開時にすぐ実行し、そこからintervalごとに実行し直す
+)
+
+// PanicError - タスクのpanicを回復したときのエラー
+type PanicError struct {
+	Value interface{} // recoverで得た値
+	Stack []byte      // panicしたときのスタックトレース
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("task panic: %v", e.Value)
+}
+
+// TimeoutPolicy - タスクがタイムアウトしたときの実行枠の扱い
+type TimeoutPolicy int
+
+const (
+	TimeoutPolicyHold    TimeoutPolicy = iota // タスクが終わるまで実行枠を空けない
+	TimeoutPolicyRelease                      // タイムアウトした時点で実行枠を空ける
+)
+
+// Schedule - 次回実行日時を決めるスケジュール
+type Schedule interface {
+	Next(now time.Time) time.Time
+}
+
 // Timer - タイマー
 type Timer struct {
 	interval         time.Duration
+	intervalSet      bool // SetIntervalで設定されたか
 	terms            []Term
 	currentTerm      int
 	ch               chan time.Time
 	timerRunning     bool
 	taskRunning      int
 	parallelRunnable bool
+	overlapPolicy    OverlapPolicy
+	maxConcurrency   int
+	limiter          *Limiter
+	pending          []time.Time // 溜まっている実行の予定日時 古い順
+	rejected         int
+	paused           bool
+	missed           int
+	stopping         bool
+	resumePolicy     ResumePolicy
+	runCtx           context.Context
+	runTask          func(ctx context.Context) error
+	wakeup           func()
+	skipHandler      func(scheduled time.Time, reason SkipReason)
 	startNow         bool
 	next             time.Time
 	timer            time.Timer
+	clock            Clock
+	calendar         Calendar
+	location         *time.Location
+	schedule         Schedule
+	errorHandler     func(err error)
+	taskTimeout      time.Duration
+	timeoutPolicy    TimeoutPolicy
+	panicHandler     func(err *PanicError)
+	crashOnPanic     bool
+	stop             context.CancelFunc
+	idle             chan struct{}
+	history          *history
+	observer         Observer
+	logger           *slog.Logger
+	tracer           Tracer
+	name             string
+	term             Term      // 今いる実行期間
+	termStart        time.Time // 今いる実行期間の開始日時
+	termStop         time.Time // 今いる実行期間の停止日時
+	inTerm           bool
 	mtx              sync.Mutex
 }
 
-// SetParallelRunnable - タスクの多重実行を許容するか
-func (t *Timer) SetParallelRunnable(runnable bool) *Timer {
+// SetClock - 時刻の取得とタイマーの生成に使うClockを設定する
+//   設定しなければtimeパッケージを使う
+func (t *Timer) SetClock(clock Clock) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.clock = clock
+	return t
+}
+
+// SetParallelRunnable - タスクの多重実行を許容するか
+func (t *Timer) SetParallelRunnable(runnable bool) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.parallelRunnable = runnable
+	t.overlapPolicy = OverlapSkip
+	if runnable {
+		t.overlapPolicy = OverlapParallel
+	}
+	return t
+}
+
+// SetOverlapPolicy - タスクの実行中に次の実行時刻が来たときの扱いを設定する
+//   OverlapParallelはSetParallelRunnable(true)と同じ
+func (t *Timer) SetOverlapPolicy(policy OverlapPolicy) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.overlapPolicy = policy
+	t.parallelRunnable = policy == OverlapParallel
+	return t
+}
+
+// SetMaxConcurrency - タスクを同時に実行する数の上限を設定する
+//   0以下なら、多重実行を許容していれば上限なし、許容していなければ1になる
+func (t *Timer) SetMaxConcurrency(n int) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.maxConcurrency = n
+	return t
+}
+
+// SetLimiter - 複数のTimerで共有する同時実行数の上限を設定する
+//   Limiterの上限に達していれば、重複時の扱いによらず実行しない
+func (t *Timer) SetLimiter(limiter *Limiter) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.limiter = limiter
+	return t
+}
+
+// RejectedCount - 実行時刻が来ても、同時実行数の上限かLimiterの上限で実行しなかった回数を返す
+//   一時停止していて実行しなかった回数は含まない
+func (t *Timer) RejectedCount() int {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.rejected
+}
+
+// SetResumePolicy - 再開したときの、一時停止中に来た実行時刻の扱いを設定する
+func (t *Timer) SetResumePolicy(policy ResumePolicy) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.resumePolicy = policy
+	return t
+}
+
+// Pause - タスクの実行を一時停止する
+//   次回実行日時はそのまま進み、一時停止中に来た実行時刻はSkipReasonPausedでスキップされる
+func (t *Timer) Pause() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.paused = true
+}
+
+// Resume - タスクの実行を再開する
+//   一時停止中に来た実行時刻は、再開したときの扱いに従う
+func (t *Timer) Resume() {
+	t.mtx.Lock()
+	if !t.paused {
+		t.mtx.Unlock()
+		return
+	}
+	t.paused = false
+	missed := t.missed
+	t.missed = 0
+	ctx, task, wakeup := t.runCtx, t.runTask, t.wakeup
+	now := t.getClock().Now()
+	realign := t.resumePolicy == ResumeRealign && wakeup != nil
+	if realign { // 次に決める次回実行日時が今になるようにする
+		t.next = now.Add(-t.interval)
+	}
+	t.mtx.Unlock()
+
+	switch {
+	case t.resumePolicy == ResumeRunOnce && missed > 0 && task != nil:
+		t.dispatch(ctx, now, task)
+	case realign:
+		wakeup()
+	}
+}
+
+// IsPaused - 一時停止中か
+func (t *Timer) IsPaused() bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.paused
+}
+
+// Trigger - スケジュールとは別に、実行中のタイマーのタスクをすぐに1回実行する
+//   実行したかを返し、同時実行数などの上限に達していれば実行しない
+//   次回実行日時は変わらない
+func (t *Timer) Trigger() (bool, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.trigger(t.runCtx)
+}
+
+// TriggerContext - ctxを実行ごとのctxの親にして、実行中のタイマーのタスクをすぐに1回実行する
+//   ctxがnilならTimerNotSetContextErrorを返す
+func (t *Timer) TriggerContext(ctx context.Context) (bool, error) {
+	if ctx == nil {
+		return false, TimerNotSetContextError
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.trigger(ctx)
+}
+
+// trigger - ロックを取った状態で、ctxを実行ごとのctxの親にしてタスクをすぐに1回実行する
+func (t *Timer) trigger(ctx context.Context) (bool, error) {
+	task := t.runTask
+	if task == nil || t.stopping {
+		return false, TimerNotRunningError
+	}
+	if ok, _ := t.tryIncrementTaskRunning(); !ok {
+		return false, nil
+	}
+	go t.execute(ctx, t.getClock().Now(), task)
+	return true, nil
+}
+
+// SetHistorySize - 実行の記録を最大size件まで残すようにする
+//   0以下なら記録しない
+func (t *Timer) SetHistorySize(size int) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.history = nil
+	if size > 0 {
+		t.history = newHistory(size)
+	}
+	return t
+}
+
+// History - 残っている実行の記録を古い順に返す
+func (t *Timer) History() []Execution {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.history == nil {
+		return nil
+	}
+	return t.history.list()
+}
+
+// record - 記録を残す設定なら実行の記録を残し、ExecutionObserverに渡す
+func (t *Timer) record(e Execution) {
+	t.mtx.Lock()
+	if t.history != nil {
+		t.history.add(e)
+	}
+	t.mtx.Unlock()
+
+	if o, ok := t.getObserver().(ExecutionObserver); ok {
+		o.OnExecution(e)
+	}
+}
+
+// SetObserver - タイマーの実行中に起きたことを受け取るObserverを設定する
+func (t *Timer) SetObserver(observer Observer) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.observer = observer
+	return t
+}
+
+// SetLogger - 次回実行日時の決め方やタスクの実行結果を記録するロガーを設定する
+//   記録はDebugからErrorまでのレベルで残すので、残すレベルはロガーのハンドラで絞る
+func (t *Timer) SetLogger(logger *slog.Logger) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.logger = logger
+	return t
+}
+
+// SetName - タイマーの名前を設定する
+//   名前はSpanの名前や属性に使う
+func (t *Timer) SetName(name string) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.name = name
+	return t
+}
+
+// Name - タイマーの名前を返す
+func (t *Timer) Name() string {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.name
+}
+
+// SetTracer - タスクの実行ごとにSpanを作るトレーサーを設定する
+//   Spanを持たせたctxが、実行ごとのctxの親になる
+func (t *Timer) SetTracer(tracer Tracer) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.tracer = tracer
+	return t
+}
+
+// SetSkipHandler - 実行時刻が来てもタスクを実行しなかったことを受け取るハンドラを設定する
+func (t *Timer) SetSkipHandler(handler func(scheduled time.Time, reason SkipReason)) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.skipHandler = handler
+	return t
+}
+
+// SetStartNow - タスクの初回実行をすぐに行うか
+func (t *Timer) SetStartNow(startNow bool) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.startNow = startNow
+	return t
+}
+
+// SetCalendar - 休日を判定するカレンダーを設定する
+//   休日に開始する期間では実行しない
+func (t *Timer) SetCalendar(calendar Calendar) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.calendar = calendar
+	return t
+}
+
+// SetLocation - 期間や次回実行日時の計算に使うロケーションを設定する
+//   設定しなければtime.Localを使う
+func (t *Timer) SetLocation(location *time.Location) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.location = location
+	return t
+}
+
+// SetSchedule - 次回実行日時をスケジュールから決めるようにする
+//   スケジュールを設定すると、期間とintervalは使わず、Runのintervalは0でもよい
+func (t *Timer) SetSchedule(schedule Schedule) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.schedule = schedule
+	return t
+}
+
+// SetErrorHandler - タスクが返したエラーを受け取るハンドラを設定する
+func (t *Timer) SetErrorHandler(handler func(err error)) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.errorHandler = handler
+	return t
+}
+
+// SetTaskTimeout - タスク1回の実行時間の上限を設定する
+//   上限を超えるとタスクのctxをキャンセルし、エラーハンドラにTimerTaskTimeoutErrorを渡す
+func (t *Timer) SetTaskTimeout(timeout time.Duration) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.taskTimeout = timeout
+	return t
+}
+
+// SetTimeoutPolicy - タスクがタイムアウトしたときに実行枠を空けるタイミングを設定する
+func (t *Timer) SetTimeoutPolicy(policy TimeoutPolicy) *Timer {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.timerRunning {
+		return t
+	}
+
+	t.timeoutPolicy = policy
+	return t
+}
+
+// SetPanicHandler - タスクのpanicを受け取るハンドラを設定する
+//   設定しなければ、panicはPanicErrorとしてエラーハンドラに渡す
+func (t *Timer) SetPanicHandler(handler func(err *PanicError)) *Timer {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
 
@@ -39,12 +535,12 @@ func (t *Timer) SetParallelRunnable(runnable bool) *Timer {
 		return t
 	}
 
-	t.parallelRunnable = runnable
+	t.panicHandler = handler
 	return t
 }
 
-// SetStartNow - タスクの初回実行をすぐに行うか
-func (t *Timer) SetStartNow(startNow bool) *Timer {
+// SetCrashOnPanic - タスクのpanicを報告した後、回復せずにもう一度panicさせるか
+func (t *Timer) SetCrashOnPanic(crash bool) *Timer {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
 
@@ -52,7 +548,7 @@ func (t *Timer) SetStartNow(startNow bool) *Timer {
 		return t
 	}
 
-	t.startNow = startNow
+	t.crashOnPanic = crash
 	return t
 }
 
@@ -65,105 +561,695 @@ func (t *Timer) AddTerm(term Term) *Timer {
 		return t
 	}
 
-	if t.terms == nil {
-		t.terms = []Term{}
+	t.terms = addTerm(t.terms, term)
+	return t
+}
+
+// UpdateTerms - 実行期間をtermsで置き換える
+//   実行中でも使え、次に決める次回実行日時から反映される
+func (t *Timer) UpdateTerms(terms ...Term) error {
+	if len(terms) == 0 {
+		return TimerNoTermsError
+	}
+
+	newTerms := []Term{}
+	for _, term := range terms {
+		newTerms = addTerm(newTerms, term)
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.terms = newTerms
+	return nil
+}
+
+// RemoveTerm - 実行期間を削除する
+//   実行中でも使え、次に決める次回実行日時から反映される
+//   期間が見つからないか、最後の期間を削除しようとしたらerrorを返す
+func (t *Timer) RemoveTerm(term Term) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	for i, tt := range t.terms {
+		if !tt.Equal(term) {
+			continue
+		}
+		if len(t.terms) == 1 {
+			return TimerNoTermsError
+		}
+		terms := make([]Term, 0, len(t.terms)-1)
+		terms = append(terms, t.terms[:i]...)
+		t.terms = append(terms, t.terms[i+1:]...)
+		return nil
+	}
+	return TimerTermNotFoundError
+}
+
+// SetInterval - 実行間隔を変更する
+//   実行中でも使え、次に決める次回実行日時から反映される
+//   実行前に設定すれば、RunやScheduler.Addに渡したintervalより優先され、intervalは0でもよい
+func (t *Timer) SetInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return TimerNotSetIntervalError
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.interval = interval
+	t.intervalSet = true
+	return nil
+}
+
+// addTerm - termsに期間を追加して並び替えたものを返す
+func addTerm(terms []Term, term Term) []Term {
+	if terms == nil {
+		terms = []Term{}
 	}
 	// 重複チェック 同じ期間があれば追加しない
-	for _, tt := range t.terms {
+	for _, tt := range terms {
 		if tt.Equal(term) {
-			return t
+			return terms
 		}
 	}
-	t.terms = append(t.terms, term)
+	terms = append(terms, term)
 
 	// startが小さいか、startが同じなら実行時間が長いのを前にする
-	sort.Slice(t.terms, func(i, j int) bool {
-		return t.terms[i].start < t.terms[j].start ||
-			(t.terms[i].start == t.terms[j].start && t.terms[i].runnableSecond() > t.terms[j].runnableSecond())
+	sort.Slice(terms, func(i, j int) bool {
+		return terms[i].startTime() < terms[j].startTime() ||
+			(terms[i].startTime() == terms[j].startTime() && terms[i].runnableSecond() > terms[j].runnableSecond())
 	})
-	return t
+	return terms
 }
 
 // Run - タイマーの開始
+//   SetIntervalで実行間隔を設定していれば、intervalは使わない
 func (t *Timer) Run(ctx context.Context, interval time.Duration, task func()) error {
+	if task == nil {
+		return t.RunContext(ctx, interval, nil)
+	}
+	return t.RunContext(ctx, interval, func(context.Context) error {
+		task()
+		return nil
+	})
+}
+
+// RunContext - ctxを受け取りエラーを返すタスクでタイマーを開始する
+//   タスクにはctxから派生した実行ごとのctxを渡し、返されたエラーはエラーハンドラに渡す
+func (t *Timer) RunContext(ctx context.Context, interval time.Duration, task func(ctx context.Context) error) error {
 	if ctx == nil {
 		return TimerNotSetContextError
 	}
-	if interval <= 0 {
+	if interval <= 0 && !t.hasInterval() {
 		return TimerNotSetIntervalError
 	}
 	if task == nil {
 		return TimerNotSetTaskError
 	}
 
+	loopCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	started, err := t.start(interval, stop)
+	if err != nil {
+		return err
+	}
+	started()
+	defer t.finish()
+	wake := make(chan struct{}, 1)
+	t.bind(ctx, task, func() {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	})
+
+	clock := t.getClock()
+	// 次の実行時刻を決定 スケジュールに次回実行日時がなければ終了か次回実行日時の決め直しを待つ
+	next, scheduled := t.advance(clock.Now())
+	for {
+		scheduled()
+		now := clock.Now()
+
+		// 今いる実行期間の停止日時が次回実行日時より前なら、先に停止日時を待つ
+		at, exiting := t.wakeAt(next)
+		var c <-chan time.Time
+		var tm ClockTimer
+		if !at.IsZero() {
+			tm = clock.NewTimer(at.Sub(now))
+			c = tm.C()
+		}
+		select {
+		case <-c:
+			if exiting { // 停止日時が来たら期間から出て、同じ次回実行日時を待ち直す
+				t.exitTerm(clock.Now())
+				scheduled = func() {}
+				continue
+			}
+			t.dispatch(ctx, next, task) // 実行時間が来たら非同期で実行
+		case <-wake: // 次回実行日時を決め直す
+			if tm != nil {
+				tm.Stop()
+			}
+		case <-loopCtx.Done(): // ctxの終了かShutdownならreturn nil
+			if tm != nil {
+				tm.Stop() // そのまま捨てられるタイマーなので発火済みかなど気にしない
+			}
+			return nil
+		}
+		next, scheduled = t.advance(clock.Now())
+	}
+}
+
+// hasInterval - 渡されたintervalがなくても実行できるように、スケジュールかSetIntervalで設定された実行間隔があるか
+func (t *Timer) hasInterval() bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.schedule != nil || t.intervalSet
+}
+
+// start - タイマーを実行中にして、Observerとロガーに知らせる関数を返す
+//   stopはShutdownで実行を止めるのに使う
+//   知らせる関数は、呼び出し側がロックを外してから呼ぶ
+func (t *Timer) start(interval time.Duration, stop context.CancelFunc) (func(), error) {
 	// 開始してフラグを立てるまでは排他ロック
 	t.mtx.Lock()
+
 	if t.timerRunning {
 		t.mtx.Unlock()
-		return TimerIsRunningError
+		return nil, TimerIsRunningError
 	}
 	t.timerRunning = true
-	defer func() {
-		t.mtx.Lock()
-		t.timerRunning = false
-		t.mtx.Unlock()
-	}()
-	t.mtx.Unlock()
-	t.interval = interval
+	t.stopping = false
+	t.stop = stop
+	if !t.intervalSet {
+		t.interval = interval
+	}
+	interval = t.interval
 	if t.terms == nil {
 		t.terms = append(t.terms, NewTerm(NewTime(0, 0, 0), NewTime(23, 59, 59)))
 	}
+	t.mtx.Unlock()
+
+	return func() {
+		t.log(slog.LevelInfo, "timer started", slog.Duration("interval", interval))
+		t.getObserver().OnStart()
+	}, nil
+}
+
+// finish - タイマーを実行中でなくする
+//   実行期間にいれば、期間から出たことにする
+func (t *Timer) finish() {
+	t.mtx.Lock()
+	t.timerRunning = false
+	t.stop = nil
+	t.pending = nil
+	t.runCtx, t.runTask, t.wakeup = nil, nil, nil
+	term, inTerm := t.term, t.inTerm
+	t.inTerm = false
+	t.mtx.Unlock()
+
+	observer := t.getObserver()
+	if inTerm {
+		t.log(slog.LevelInfo, "term exited", termAttr(term))
+		observer.OnTermExit(term)
+	}
+	t.log(slog.LevelInfo, "timer stopped")
+	observer.OnStop()
+}
 
-	for {
-		now := time.Now()
+// bind - 実行中のタイマーが、スケジュール外でタスクを実行したり次回実行日時を決め直したりするのに使うものを設定する
+func (t *Timer) bind(ctx context.Context, task func(ctx context.Context) error, wakeup func()) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
 
-		// 次の実行時刻を決定
-		t.next = t.nextTime(now)
-		d := t.next.Sub(now)
-		tm := time.NewTimer(d)
-		select {
-		case <-tm.C: // 実行時間が来たら非同期で実行
-			go func() {
-				if t.incrementTaskRunning() { // タスク実行中でないか、多重起動許容の場合にタスクを実行する
-					defer t.decrementTaskRunning()
-					task()
-				}
-			}()
-		case <-ctx.Done(): // ctxの終了ならreturn nil
-			tm.Stop() // そのまま捨てられるタイマーなので発火済みかなど気にしない
-			return nil
+	t.runCtx, t.runTask, t.wakeup = ctx, task, wakeup
+}
+
+// advance - 次回実行日時を決めて、Observerとロガーに知らせる関数と一緒に返す
+//   次回実行日時が今いる実行期間の外で、期間の停止日時を過ぎていれば、期間から出たことにする
+//   停止日時がまだなら、停止日時が来たときにexitTermで期間から出る
+//   知らせる関数は、呼び出し側がロックを外してから呼ぶ
+func (t *Timer) advance(now time.Time) (time.Time, func()) {
+	t.mtx.Lock()
+	next, entries := t.nextTime(now)
+	t.next = next
+	term, exited := t.term, false
+	var nextTerm Term
+	var start time.Time
+	inNextTerm := false
+	if !next.IsZero() {
+		nextTerm, start, inNextTerm = t.termAt(next)
+	}
+	if t.inTerm && (next.IsZero() || !inNextTerm || !start.Equal(t.termStart)) && !now.Before(t.termStop) {
+		t.inTerm, exited = false, true
+	}
+	inNextTerm = inNextTerm && t.schedule == nil
+	t.mtx.Unlock()
+
+	return next, func() {
+		t.logEntries(entries)
+		observer := t.getObserver()
+		if exited {
+			t.log(slog.LevelInfo, "term exited", termAttr(term))
+			observer.OnTermExit(term)
+		}
+		if next.IsZero() {
+			t.log(slog.LevelInfo, "no next run")
+			return
+		}
+		if inNextTerm {
+			t.log(slog.LevelDebug, "next run scheduled", slog.Time("next", next), termAttr(nextTerm))
+		} else {
+			t.log(slog.LevelDebug, "next run scheduled", slog.Time("next", next))
+		}
+		observer.OnScheduled(next)
+	}
+}
+
+// enterTerm - 実行日時を含む実行期間に、まだいなければ入ったことにする
+func (t *Timer) enterTerm(scheduled time.Time) {
+	t.mtx.Lock()
+	term, start, ok := t.termAt(scheduled)
+	if !ok || (t.inTerm && start.Equal(t.termStart)) {
+		t.mtx.Unlock()
+		return
+	}
+	prev, exited := t.term, t.inTerm
+	_, stop := term.boundary(scheduled.In(t.getLocation()))
+	t.term, t.termStart, t.termStop, t.inTerm = term, start, stop, true
+	t.mtx.Unlock()
+
+	observer := t.getObserver()
+	if exited {
+		t.log(slog.LevelInfo, "term exited", termAttr(prev))
+		observer.OnTermExit(prev)
+	}
+	t.log(slog.LevelInfo, "term entered", termAttr(term), slog.Time("start", start))
+	observer.OnTermEnter(term)
+}
+
+// wakeAt - 次回実行日時と今いる実行期間の停止日時のうち、先に待つ日時と、それが停止日時かを返す
+//   次回実行日時がその期間の中なら、停止日時は待たない
+func (t *Timer) wakeAt(next time.Time) (time.Time, bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.inTerm && (next.IsZero() || t.termStop.Before(next)) {
+		return t.termStop, true
+	}
+	return next, false
+}
+
+// exitTerm - 今いる実行期間の停止日時を過ぎていれば、期間から出たことにする
+func (t *Timer) exitTerm(now time.Time) {
+	t.mtx.Lock()
+	if !t.inTerm || now.Before(t.termStop) {
+		t.mtx.Unlock()
+		return
+	}
+	term := t.term
+	t.inTerm = false
+	t.mtx.Unlock()
+
+	t.log(slog.LevelInfo, "term exited", termAttr(term))
+	t.getObserver().OnTermExit(term)
+}
+
+// dispatch - 実行時刻が来たタスクを、実行枠が取れれば非同期で実行する
+//   実行枠が取れなければ重複時の扱いに従って溜めるか、実行しなかったことをスキップハンドラに渡す
+func (t *Timer) dispatch(ctx context.Context, scheduled time.Time, task func(ctx context.Context) error) {
+	if o, ok := t.getObserver().(TickObserver); ok {
+		o.OnTick(scheduled)
+	}
+	t.enterTerm(scheduled)
+	acquired, skipped, reason := t.acquireTaskRunning(scheduled)
+	if acquired {
+		go t.execute(ctx, scheduled, task)
+	}
+	if skipped {
+		t.record(Execution{Scheduled: scheduled, Outcome: OutcomeSkipped, Reason: reason})
+		t.log(slog.LevelInfo, "run skipped", slog.Time("scheduled", scheduled), slog.String("reason", reason.String()))
+		t.getObserver().OnSkip(scheduled, reason)
+		if t.skipHandler != nil {
+			t.skipHandler(scheduled, reason)
+		}
+	}
+}
+
+// execute - 実行ごとのctxでタスクを実行し、エラーがあればエラーハンドラに渡す
+//   タスクが終わったら実行枠を返すが、TimeoutPolicyReleaseならタイムアウトした時点で返す
+//   溜まっている実行があれば、実行枠を引き継いで次のタスクを実行する
+func (t *Timer) execute(ctx context.Context, scheduled time.Time, task func(ctx context.Context) error) {
+	clock := t.getClock()
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			// 溜まっていた実行は、Triggerで渡されたctxではなくタイマーの実行に渡されたctxで実行する
+			if runCtx, next, ok := t.decrementTaskRunning(); ok {
+				go t.execute(runCtx, next, task)
+			}
+		})
+	}
+	defer release()
+
+	spanCtx, span := t.startSpan(ctx, scheduled)
+	taskCtx, cancel := withTimeout(spanCtx, clock, t.taskTimeout)
+	defer cancel()
+	if t.taskTimeout > 0 && t.timeoutPolicy == TimeoutPolicyRelease {
+		go func() {
+			<-taskCtx.Done()
+			if taskCtx.timedOut() {
+				release()
+			}
+		}()
+	}
+
+	observer := t.getObserver()
+	e := Execution{Scheduled: scheduled, Start: clock.Now()}
+	observer.OnTaskStart(scheduled)
+	err := call(taskCtx, task)
+	e.End = clock.Now()
+	var pe *PanicError
+	if errors.As(err, &pe) {
+		e.Outcome, e.Err = OutcomePanic, err
+		t.record(e)
+		endSpan(span, e)
+		t.log(slog.LevelError, "task panicked", slog.Time("scheduled", scheduled), slog.Duration("duration", e.Duration()),
+			slog.Any("panic", pe.Value), slog.String("stack", string(pe.Stack)))
+		observer.OnTaskEnd(e.Duration(), err)
+		if t.panicHandler != nil {
+			t.panicHandler(pe)
+		} else if t.errorHandler != nil {
+			t.errorHandler(err)
+		}
+		if t.crashOnPanic {
+			panic(pe.Value)
+		}
+		return
+	}
+
+	if taskCtx.timedOut() {
+		if err == nil {
+			err = TimerTaskTimeoutError
+		} else {
+			err = fmt.Errorf("%w: %v", TimerTaskTimeoutError, err)
+		}
+		e.Outcome = OutcomeTimeout
+	} else if err != nil {
+		e.Outcome = OutcomeError
+	}
+	e.Err = err
+	t.record(e)
+	endSpan(span, e)
+	switch e.Outcome {
+	case OutcomeTimeout:
+		t.log(slog.LevelWarn, "task timed out", slog.Time("scheduled", scheduled), slog.Duration("duration", e.Duration()), slog.Any("error", err))
+	case OutcomeError:
+		t.log(slog.LevelWarn, "task failed", slog.Time("scheduled", scheduled), slog.Duration("duration", e.Duration()), slog.Any("error", err))
+	default:
+		t.log(slog.LevelDebug, "task finished", slog.Time("scheduled", scheduled), slog.Duration("duration", e.Duration()))
+	}
+	observer.OnTaskEnd(e.Duration(), err)
+	if err != nil && t.errorHandler != nil {
+		t.errorHandler(err)
+	}
+}
+
+// call - タスクを呼び出し、panicしたら回復してPanicErrorを返す
+func call(ctx context.Context, task func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
 		}
+	}()
+	return task(ctx)
+}
+
+// getClock - 設定されたClockを返す、未設定ならtimeパッケージを使うClockを返す
+func (t *Timer) getClock() Clock {
+	if t.clock == nil {
+		return realClock{}
+	}
+	return t.clock
+}
+
+// log - ロガーが設定されていれば記録を残す
+func (t *Timer) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	if t.logger == nil {
+		return
+	}
+	t.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// logEntry - ロックを取っている間に決まり、ロックを外してから残すログ
+type logEntry struct {
+	level slog.Level
+	msg   string
+	attrs []slog.Attr
+}
+
+// logEntries - とっておいたログを残す
+//   ロガーのハンドラからタイマーを操作できるように、ロックを外してから呼ぶ
+func (t *Timer) logEntries(entries []logEntry) {
+	for _, e := range entries {
+		t.log(e.level, e.msg, e.attrs...)
+	}
+}
+
+// termAttr - 期間をログに残す形にする
+func termAttr(term Term) slog.Attr {
+	return slog.String("term", term.String())
+}
+
+// getObserver - 設定されたObserverを返す、未設定なら何もしないObserverを返す
+func (t *Timer) getObserver() Observer {
+	if t.observer == nil {
+		return NopObserver{}
 	}
+	return t.observer
+}
+
+// getLocation - 設定されたロケーションを返す、未設定ならtime.Localを返す
+func (t *Timer) getLocation() *time.Location {
+	if t.location == nil {
+		return time.Local
+	}
+	return t.location
 }
 
 // incrementTaskRunning - 実行中のタスクのカウントを増やす
-//   ただし、多重起動不可なら複数起動はしないので、その場合は実質上限1
+//   ただし、Shutdownで止めているか、同時実行数の上限かLimiterの上限に達していれば増やさない
 func (t *Timer) incrementTaskRunning() bool {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
 
-	// タスク実行中かつ、多重起動不可ならロックが取れない
-	if t.taskRunning > 0 && !t.parallelRunnable {
+	if t.stopping {
 		return false
 	}
+	ok, _ := t.tryIncrementTaskRunning()
+	return ok
+}
+
+// tryIncrementTaskRunning - ロックを取った状態で、実行中のタスクのカウントを増やせれば増やす
+//   増やせなければ、その理由を返す
+func (t *Timer) tryIncrementTaskRunning() (bool, SkipReason) {
+	// 上限の指定がなく、多重起動不可なら上限は1
+	limit := t.maxConcurrency
+	if limit <= 0 && !t.parallelRunnable {
+		limit = 1
+	}
+	if limit > 0 && t.taskRunning >= limit {
+		return false, SkipReasonOverlap
+	}
+	if t.limiter != nil && !t.limiter.acquire() {
+		return false, SkipReasonLimiter
+	}
 
 	t.taskRunning++
-	return true
+	return true, 0
+}
+
+// acquireTaskRunning - 実行中のタスクのカウントを増やせればtrueを返す
+//   増やせなければ重複時の扱いに従って予定日時ごと実行を溜め、溜めなかった場合は実行しない理由を返す
+//   Shutdownで止めていれば、スキップにもせずに実行しない
+func (t *Timer) acquireTaskRunning(scheduled time.Time) (acquired bool, skipped bool, reason SkipReason) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.stopping {
+		return false, false, 0
+	}
+	// 一時停止による実行しなかった回数は、上限による実行しなかった回数には含めない
+	if t.paused {
+		t.missed++
+		return false, true, SkipReasonPaused
+	}
+
+	ok, reason := t.tryIncrementTaskRunning()
+	if ok {
+		return true, false, 0
+	}
+
+	// Limiterの上限は他のTimerのタスクによるもので、このTimerのタスクの終了で空くとは限らないので溜めない
+	if reason == SkipReasonOverlap {
+		switch t.overlapPolicy {
+		case OverlapQueue:
+			t.pending = append(t.pending, scheduled)
+			return false, false, 0
+		case OverlapCoalesce: // 最初に溜めた予定日時で実行し、後から来た実行時刻はまとめる
+			if len(t.pending) == 0 {
+				t.pending = append(t.pending, scheduled)
+				return false, false, 0
+			}
+			reason = SkipReasonCoalesced
+		}
+	}
+	t.rejected++
+	return false, true, reason
 }
 
 // decrementTaskRunning - 実行中のタスクのカウントを減らす
-func (t *Timer) decrementTaskRunning() {
+//   溜まっている実行があれば、カウントを減らさずに一番古い溜まっている実行を取り出し、
+//   タイマーの実行に渡されたctxと予定日時とtrueを返す
+//   Shutdownで止めているか、タイマーの実行が終わっていれば、溜まっている実行は捨てる
+//   実行中のタスクがなくなったら、待っているWaitに知らせる
+func (t *Timer) decrementTaskRunning() (context.Context, time.Time, bool) {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
+
+	if len(t.pending) > 0 && !t.stopping && t.runCtx != nil {
+		scheduled := t.pending[0]
+		t.pending = t.pending[1:]
+		return t.runCtx, scheduled, true
+	}
+	t.pending = nil
+
 	t.taskRunning--
+	if t.limiter != nil {
+		t.limiter.release()
+	}
+	if t.taskRunning == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+	return nil, time.Time{}, false
+}
+
+// Shutdown - タイマーを止めて、実行中のタスクが終わるのを待つ
+//   止めた後に実行時刻が来たり、Triggerされたりしてもタスクは実行せず、溜まっている実行も捨てる
+//   ctxが終了するまでに終わらなかったタスクの数と、ctxのエラーを返す
+func (t *Timer) Shutdown(ctx context.Context) (int, error) {
+	t.mtx.Lock()
+	if t.stop != nil {
+		// 実行枠を取るのと同じロックの中で止めるので、Waitの後に始まるタスクはない
+		t.stopping = true
+		t.stop()
+	}
+	t.mtx.Unlock()
+
+	return t.Wait(ctx)
+}
+
+// Wait - 実行中のタスクが終わるのを待つ
+//   ctxが終了するまでに終わらなかったタスクの数と、ctxのエラーを返す
+func (t *Timer) Wait(ctx context.Context) (int, error) {
+	t.mtx.Lock()
+	if t.taskRunning == 0 {
+		t.mtx.Unlock()
+		return 0, nil
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.mtx.Unlock()
+
+	select {
+	case <-idle:
+		return 0, nil
+	case <-ctx.Done():
+		t.mtx.Lock()
+		defer t.mtx.Unlock()
+		return t.taskRunning, ctx.Err()
+	}
+}
+
+// NextRun - 次回実行日時を返す
+//   実行中なら待っている実行日時を、実行中でなければ今から実行したときの最初の実行日時を返し、なければゼロ値を返す
+func (t *Timer) NextRun() time.Time {
+	t.mtx.Lock()
+	if t.timerRunning && !t.next.IsZero() {
+		defer t.mtx.Unlock()
+		return t.next
+	}
+	t.mtx.Unlock()
+
+	if runs := t.NextRuns(t.getClock().Now(), 1); len(runs) > 0 {
+		return runs[0]
+	}
+	return time.Time{}
+}
+
+// NextRuns - fromから実行したときの実行日時を、Runと同じ決め方で最大n個返す
+//   実行中なら待っている実行日時から続けて決め、from以降のものを返す
+//   intervalはRunかSetIntervalで設定されたものを使い、intervalもスケジュールもなければnilを返す
+func (t *Timer) NextRuns(from time.Time, n int) []time.Time {
+	t.mtx.Lock()
+	sim := &Timer{
+		interval: t.interval,
+		terms:    t.terms,
+		startNow: t.startNow,
+		clock:    t.clock,
+		calendar: t.calendar,
+		location: t.location,
+		schedule: t.schedule,
+	}
+	if t.timerRunning {
+		sim.next = t.next
+	}
+	t.mtx.Unlock()
+
+	if n <= 0 || (sim.interval <= 0 && sim.schedule == nil) {
+		return nil
+	}
+	if sim.terms == nil {
+		sim.terms = []Term{NewTerm(NewTime(0, 0, 0), NewTime(23, 59, 59))}
+	}
+
+	runs := make([]time.Time, 0, n)
+	now := from
+	if !sim.next.IsZero() {
+		now = sim.next
+		if !sim.next.Before(from) {
+			runs = append(runs, sim.next)
+		}
+	}
+	for len(runs) < n {
+		sim.next, _ = sim.nextTime(now)
+		if sim.next.IsZero() {
+			break
+		}
+		if !sim.next.Before(from) {
+			runs = append(runs, sim.next)
+		}
+		now = sim.next
+	}
+	return runs
 }
 
 // nextTime - 次回実行日時を取得する
-func (t *Timer) nextTime(now time.Time) time.Time {
+//   本来の決め方から外れた理由は、ロックを外してから残すログとして返す
+func (t *Timer) nextTime(now time.Time) (time.Time, []logEntry) {
+	now = now.In(t.getLocation())
+
+	if t.schedule != nil {
+		return t.scheduleNext(now)
+	}
+
 	// nextがzeroタイムなら直近の開始日時を設定する、zeroタイムでなければ前回実行日時 + intervalを設定する
 	if t.next.IsZero() {
 		if t.startNow && t.runnable(now) {
-			return now
+			return now, nil
 		} else {
 			return t.nextStart(now)
 		}
@@ -172,34 +1258,93 @@ func (t *Timer) nextTime(now time.Time) time.Time {
 
 		// 次回実行日時が実行可能でなければ、次の開始時刻を採用する
 		if !t.runnable(nt) {
-			nt = t.nextStart(now)
+			entries := []logEntry{{level: slog.LevelDebug, msg: "interval run is out of terms, moving to the next term start",
+				attrs: []slog.Attr{slog.Time("candidate", nt)}}}
+			nt, fallback := t.nextStart(now)
+			return nt, append(entries, fallback...)
+		}
+		return nt, nil
+	}
+}
+
+// scheduleNext - スケジュールから次回実行日時を取得する
+//   休日は飛ばし、1年先まで取れなければゼロ値と、その理由のログを返す
+func (t *Timer) scheduleNext(now time.Time) (time.Time, []logEntry) {
+	if t.next.IsZero() && t.startNow && !t.isHoliday(now) {
+		return now, nil
+	}
+
+	limit := now.AddDate(1, 0, 0)
+	nt := t.schedule.Next(now)
+	for !nt.IsZero() && t.isHoliday(nt) {
+		if nt.After(limit) {
+			return time.Time{}, []logEntry{{level: slog.LevelWarn, msg: "schedule has no run on a non-holiday within a year",
+				attrs: []slog.Attr{slog.Time("from", now)}}}
 		}
-		return nt
+		nt = t.schedule.Next(nt)
 	}
+	return nt, nil
 }
 
 // nextStart - 次の開始日時を取得する
-//   期間から次の開始日時が取れなかった場合、翌日の0時を返す
-func (t *Timer) nextStart(now time.Time) time.Time {
-	for i := 0; i <= 1; i++ {
+//   曜日の指定や休日があるので1年先まで探し、期間から次の開始日時が取れなかった場合、翌日の0時と、その理由のログを返す
+//   夏時間で存在しない開始時刻は飛ばされた分だけ後ろにずらし、2回ある開始時刻は1回目を使う
+func (t *Timer) nextStart(now time.Time) (time.Time, []logEntry) {
+	now = now.In(t.getLocation())
+	for i := 0; i <= 366; i++ {
 		for _, term := range t.terms {
-			nt := time.Date(now.Year(), now.Month(), now.Day(), term.start.hour(), term.start.minute(), term.start.second(), 0, time.Local)
-			nt = nt.AddDate(0, 0, i)
-			if nt.After(now) {
-				return nt
+			nt := localDate(now.Year(), now.Month(), now.Day()+i, term.start, false, now.Location()).Add(time.Duration(term.startNsec))
+			if nt.After(now) && term.weekdays.has(nt.Weekday()) && !t.isHoliday(nt) {
+				return nt, nil
 			}
 		}
 	}
-	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	return localDate(now.Year(), now.Month(), now.Day()+1, NewTime(0, 0, 0), false, now.Location()),
+		[]logEntry{{level: slog.LevelWarn, msg: "no term starts within a year, falling back to the next midnight",
+			attrs: []slog.Attr{slog.Time("from", now)}}}
 }
 
 // runnable - Timerの持つtermsをすべて見て、実行可能かを返す
+//   休日に開始した期間は実行可能としない
 func (t *Timer) runnable(now time.Time) bool {
+	_, _, ok := t.termAt(now)
+	return ok
+}
+
+// termIndex - 日時を含む実行期間の位置を返す なければ-1を返す
+func (t *Timer) termIndex(at time.Time) int {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	term, _, ok := t.termAt(at)
+	if !ok {
+		return -1
+	}
+	for i, tt := range t.terms {
+		if tt.Equal(term) {
+			return i
+		}
+	}
+	return -1
+}
+
+// termAt - nowを含む実行期間と、その開始日時を返す
+//   開始日時が休日の期間は含まない
+func (t *Timer) termAt(now time.Time) (Term, time.Time, bool) {
+	now = now.In(t.getLocation())
 	for _, term := range t.terms {
-		if term.runnable(now) {
-			return true
+		if !term.runnable(now) {
+			continue
+		}
+		if start, _ := term.boundary(now); !t.isHoliday(start) {
+			return term, start, true
 		}
 	}
 
-	return false
+	return Term{}, time.Time{}, false
+}
+
+// isHoliday - カレンダーが設定されていて、dateが休日ならtrue
+func (t *Timer) isHoliday(date time.Time) bool {
+	return t.calendar != nil && t.calendar.IsHoliday(date)
 }
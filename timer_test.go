@@ -1,7 +1,10 @@
 package gotimer
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"log/slog"
 	"reflect"
 	"sync"
 	"testing"
@@ -21,6 +24,7 @@ func Test_Timer_Run(t *testing.T) {
 		{name: "ctxが未設定ならerror", timer: &Timer{}, want: TimerNotSetContextError},
 		{name: "intervalが1未満ならerror", timer: &Timer{}, ctx: context.Background(), want: TimerNotSetIntervalError},
 		{name: "taskがnilならerror", timer: &Timer{}, ctx: context.Background(), interval: 15 * time.Second, want: TimerNotSetTaskError},
+		{name: "スケジュールがあればintervalが0でもよい", timer: &Timer{schedule: &CronSchedule{}}, ctx: context.Background(), want: TimerNotSetTaskError},
 		{name: "isRunningがtrueならerror", timer: &Timer{timerRunning: true}, ctx: context.Background(), interval: 15 * time.Second, task: func() {}, want: TimerIsRunningError},
 	}
 
@@ -216,7 +220,7 @@ func Test_Timer_nextTime(t *testing.T) {
 		test := test
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
-			got := test.timer.nextTime(test.now)
+			got, _ := test.timer.nextTime(test.now)
 			if !reflect.DeepEqual(test.want, got) {
 				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
 			}
@@ -227,10 +231,11 @@ func Test_Timer_nextTime(t *testing.T) {
 func Test_Timer_runnable(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
-		name  string
-		terms []Term
-		now   time.Time
-		want  bool
+		name     string
+		terms    []Term
+		calendar Calendar
+		now      time.Time
+		want     bool
 	}{
 		{name: "termsのいずれかがtrueならtrueを返す",
 			terms: []Term{
@@ -250,13 +255,28 @@ func Test_Timer_runnable(t *testing.T) {
 			},
 			now:  time.Date(2020, 12, 28, 5, 30, 0, 0, time.Local),
 			want: false},
+		{name: "期間内でも休日ならfalseを返す",
+			terms:    []Term{NewTerm(NewTime(9, 0, 0), NewTime(15, 0, 0))},
+			calendar: NewStaticCalendar(time.Date(2020, 12, 31, 0, 0, 0, 0, time.Local)),
+			now:      time.Date(2020, 12, 31, 10, 0, 0, 0, time.Local),
+			want:     false},
+		{name: "日をまたぐ期間は開始日が休日でなければtrueを返す",
+			terms:    []Term{NewTerm(NewTime(16, 30, 0), NewTime(5, 30, 0))},
+			calendar: NewStaticCalendar(time.Date(2020, 12, 31, 0, 0, 0, 0, time.Local)),
+			now:      time.Date(2020, 12, 31, 5, 0, 0, 0, time.Local),
+			want:     true},
+		{name: "日をまたぐ期間は開始日が休日ならfalseを返す",
+			terms:    []Term{NewTerm(NewTime(16, 30, 0), NewTime(5, 30, 0))},
+			calendar: NewStaticCalendar(time.Date(2020, 12, 31, 0, 0, 0, 0, time.Local)),
+			now:      time.Date(2021, 1, 1, 5, 0, 0, 0, time.Local),
+			want:     false},
 	}
 
 	for _, test := range tests {
 		test := test
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
-			got := (&Timer{terms: test.terms}).runnable(test.now)
+			got := (&Timer{terms: test.terms, calendar: test.calendar}).runnable(test.now)
 			if !reflect.DeepEqual(test.want, got) {
 				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
 			}
@@ -296,13 +316,34 @@ func Test_Timer_nextStart(t *testing.T) {
 			}},
 			now:  time.Date(2020, 12, 21, 10, 05, 30, 123456789, time.Local),
 			want: time.Date(2020, 12, 21, 10, 6, 0, 0, time.Local)},
+		{name: "曜日の指定があれば、その曜日の開始日時が返される",
+			timer: &Timer{interval: 15 * time.Second, terms: []Term{
+				NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday),
+			}},
+			now:  time.Date(2020, 12, 25, 16, 0, 0, 0, time.Local),
+			want: time.Date(2020, 12, 28, 9, 0, 0, 0, time.Local)},
+		{name: "曜日の指定が当日だけなら、翌週の開始日時が返される",
+			timer: &Timer{interval: 15 * time.Second, terms: []Term{
+				NewWeekdayTerm(NewTime(9, 0, 0), NewTime(15, 0, 0), time.Saturday),
+			}},
+			now:  time.Date(2020, 12, 26, 10, 0, 0, 0, time.Local),
+			want: time.Date(2021, 1, 2, 9, 0, 0, 0, time.Local)},
+		{name: "休日は飛ばして次の開始日時が返される",
+			timer: &Timer{interval: 15 * time.Second,
+				terms: []Term{NewTerm(NewTime(9, 0, 0), NewTime(15, 0, 0))},
+				calendar: NewCompositeCalendar(WeekendCalendar{}, NewStaticCalendar(
+					time.Date(2020, 12, 31, 0, 0, 0, 0, time.Local),
+					time.Date(2021, 1, 1, 0, 0, 0, 0, time.Local),
+					time.Date(2021, 1, 4, 0, 0, 0, 0, time.Local)))},
+			now:  time.Date(2020, 12, 30, 16, 0, 0, 0, time.Local),
+			want: time.Date(2021, 1, 5, 9, 0, 0, 0, time.Local)},
 	}
 
 	for _, test := range tests {
 		test := test
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
-			got := test.timer.nextStart(test.now)
+			got, _ := test.timer.nextStart(test.now)
 			if !reflect.DeepEqual(test.want, got) {
 				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
 			}
@@ -366,3 +407,1602 @@ func Test_Timer_SetStartNow(t *testing.T) {
 		})
 	}
 }
+
+func Test_Timer_Run_FakeClock(t *testing.T) {
+	t.Parallel()
+	clock := NewFakeClock(time.Date(2021, 1, 4, 8, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).AddTerm(NewTerm(NewTime(9, 0, 0), NewTime(9, 0, 30)))
+	ch := make(chan time.Time, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = timer.Run(ctx, 10*time.Second, func() { ch <- clock.Now() }) }()
+
+	want := []time.Time{
+		time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local),
+		time.Date(2021, 1, 4, 9, 0, 10, 0, time.Local),
+		time.Date(2021, 1, 4, 9, 0, 20, 0, time.Local),
+		time.Date(2021, 1, 4, 9, 0, 30, 0, time.Local),
+		time.Date(2021, 1, 5, 9, 0, 0, 0, time.Local),
+		time.Date(2021, 1, 5, 9, 0, 10, 0, time.Local),
+	}
+	var got []time.Time
+	for _, w := range want {
+		clock.BlockUntil(1)
+		clock.Set(w)
+		got = append(got, <-ch)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Timer_SetLocation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		timerRunning bool
+		want         *time.Location
+	}{
+		{name: "timerRunningでなければ設定が反映される", timerRunning: false, want: time.UTC},
+		{name: "timerRunningであれば設定が反映されない", timerRunning: true, want: nil},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			timer := &Timer{timerRunning: test.timerRunning}
+			timer.SetLocation(time.UTC)
+			got := timer.location
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Timer_Location(t *testing.T) {
+	t.Parallel()
+	tokyo := time.FixedZone("JST", 9*60*60)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("nextStartは設定したロケーションの開始時刻を返す", func(t *testing.T) {
+		t.Parallel()
+		timer := (&Timer{}).SetLocation(tokyo).AddTerm(NewTerm(NewTime(9, 0, 0), NewTime(15, 0, 0)))
+		want := time.Date(2021, 1, 5, 0, 0, 0, 0, time.UTC)
+		got, _ := timer.nextStart(time.Date(2021, 1, 4, 1, 0, 0, 0, time.UTC))
+		if !want.Equal(got) || got.Location() != tokyo {
+			t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+		}
+	})
+
+	t.Run("runnableは設定したロケーションの時刻で判定する", func(t *testing.T) {
+		t.Parallel()
+		timer := (&Timer{}).SetLocation(tokyo).AddTerm(NewTerm(NewTime(9, 0, 0), NewTime(15, 0, 0)))
+		got1 := timer.runnable(time.Date(2021, 1, 4, 1, 0, 0, 0, time.UTC))
+		got2 := timer.runnable(time.Date(2021, 1, 4, 9, 0, 0, 0, time.UTC))
+		if !got1 || got2 {
+			t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), true, false, got1, got2)
+		}
+	})
+
+	t.Run("夏時間の開始で存在しない開始時刻は飛ばされた分だけ後ろにずらす", func(t *testing.T) {
+		t.Parallel()
+		timer := (&Timer{}).SetLocation(newYork).AddTerm(NewTerm(NewTime(2, 30, 0), NewTime(4, 0, 0)))
+		want := time.Date(2021, 3, 14, 7, 30, 0, 0, time.UTC)
+		got, _ := timer.nextStart(time.Date(2021, 3, 14, 5, 0, 0, 0, time.UTC))
+		if !want.Equal(got) {
+			t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+		}
+	})
+
+	t.Run("夏時間の終了で2回ある開始時刻は1回目を使う", func(t *testing.T) {
+		t.Parallel()
+		timer := (&Timer{}).SetLocation(newYork).AddTerm(NewTerm(NewTime(1, 30, 0), NewTime(3, 0, 0)))
+		want := time.Date(2021, 11, 7, 5, 30, 0, 0, time.UTC)
+		got, _ := timer.nextStart(time.Date(2021, 11, 7, 4, 0, 0, 0, time.UTC))
+		if !want.Equal(got) {
+			t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+		}
+	})
+
+	t.Run("夏時間の終了で2回ある停止時刻は2回目まで実行可能", func(t *testing.T) {
+		t.Parallel()
+		timer := (&Timer{}).SetLocation(newYork).AddTerm(NewTerm(NewTime(0, 0, 0), NewTime(1, 30, 0)))
+		got1 := timer.runnable(time.Date(2021, 11, 7, 6, 15, 0, 0, time.UTC)) // 2回目の01:15
+		got2 := timer.runnable(time.Date(2021, 11, 7, 6, 45, 0, 0, time.UTC)) // 2回目の01:45
+		if !got1 || got2 {
+			t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), true, false, got1, got2)
+		}
+	})
+
+	t.Run("夏時間をまたいでもintervalは実時間で進む", func(t *testing.T) {
+		t.Parallel()
+		timer := (&Timer{
+			interval: time.Hour,
+			next:     time.Date(2021, 3, 14, 6, 0, 0, 0, time.UTC),
+		}).SetLocation(newYork).AddTerm(NewTerm(NewTime(0, 0, 0), NewTime(23, 59, 59)))
+		want := time.Date(2021, 3, 14, 7, 0, 0, 0, time.UTC) // 01:00 ESTの1時間後は03:00 EDT
+		got, _ := timer.nextTime(time.Date(2021, 3, 14, 6, 0, 0, 0, time.UTC))
+		if !want.Equal(got) || got.In(newYork).Hour() != 3 {
+			t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+		}
+	})
+}
+
+func Test_Timer_Run_Schedule(t *testing.T) {
+	t.Parallel()
+	schedule, err := ParseCron("0 9 * * mon-fri")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := NewFakeClock(time.Date(2021, 1, 8, 8, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).SetSchedule(schedule)
+	ch := make(chan time.Time, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = timer.Run(ctx, 0, func() { ch <- clock.Now() }) }()
+
+	want := []time.Time{
+		time.Date(2021, 1, 8, 9, 0, 0, 0, time.Local),
+		time.Date(2021, 1, 11, 9, 0, 0, 0, time.Local),
+	}
+	var got []time.Time
+	for _, w := range want {
+		clock.BlockUntil(1)
+		clock.Set(w)
+		got = append(got, <-ch)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Timer_scheduleNext(t *testing.T) {
+	t.Parallel()
+	schedule, err := ParseCron("0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		timer *Timer
+		now   time.Time
+		want  time.Time
+	}{
+		{name: "スケジュールの次回実行日時を返す",
+			timer: &Timer{schedule: schedule},
+			now:   time.Date(2021, 1, 4, 10, 0, 0, 0, time.Local),
+			want:  time.Date(2021, 1, 5, 9, 0, 0, 0, time.Local)},
+		{name: "休日は飛ばす",
+			timer: &Timer{schedule: schedule, calendar: WeekendCalendar{}},
+			now:   time.Date(2021, 1, 8, 10, 0, 0, 0, time.Local),
+			want:  time.Date(2021, 1, 11, 9, 0, 0, 0, time.Local)},
+		{name: "即時実行可能なら現在日時を返す",
+			timer: &Timer{schedule: schedule, startNow: true},
+			now:   time.Date(2021, 1, 4, 10, 0, 0, 0, time.Local),
+			want:  time.Date(2021, 1, 4, 10, 0, 0, 0, time.Local)},
+		{name: "1年先まで休日ならゼロ値を返す",
+			timer: &Timer{schedule: schedule, calendar: alwaysHoliday{}},
+			now:   time.Date(2021, 1, 4, 10, 0, 0, 0, time.Local),
+			want:  time.Time{}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got, _ := test.timer.nextTime(test.now)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+type alwaysHoliday struct{}
+
+func (alwaysHoliday) IsHoliday(time.Time) bool { return true }
+
+func Test_Timer_RunContext(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		timer    *Timer
+		ctx      context.Context
+		interval time.Duration
+		task     func(ctx context.Context) error
+		want     error
+	}{
+		{name: "ctxが未設定ならerror", timer: &Timer{}, want: TimerNotSetContextError},
+		{name: "intervalが1未満ならerror", timer: &Timer{}, ctx: context.Background(), want: TimerNotSetIntervalError},
+		{name: "taskがnilならerror", timer: &Timer{}, ctx: context.Background(), interval: 15 * time.Second, want: TimerNotSetTaskError},
+		{name: "isRunningがtrueならerror", timer: &Timer{timerRunning: true}, ctx: context.Background(), interval: 15 * time.Second,
+			task: func(context.Context) error { return nil }, want: TimerIsRunningError},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := test.timer.RunContext(test.ctx, test.interval, test.task)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Timer_RunContext_Error(t *testing.T) {
+	t.Parallel()
+	want := errors.New("task error")
+	errCh := make(chan error, 1)
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).SetErrorHandler(func(err error) { errCh <- err })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = timer.RunContext(ctx, time.Minute, func(context.Context) error { return want }) }()
+
+	clock.BlockUntil(1)
+	clock.Advance(15 * time.Hour)
+	got := <-errCh
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Timer_RunContext_Cancel(t *testing.T) {
+	t.Parallel()
+	started := make(chan struct{})
+	errCh := make(chan error, 1)
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).SetErrorHandler(func(err error) { errCh <- err })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = timer.RunContext(ctx, time.Minute, func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(15 * time.Hour)
+	<-started
+	cancel()
+	got := <-errCh
+	if !errors.Is(got, context.Canceled) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), context.Canceled, got)
+	}
+}
+
+func Test_Timer_Shutdown(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		release   bool
+		want      int
+		wantError error
+	}{
+		{name: "タスクが終われば0とnilを返す", release: true, want: 0, wantError: nil},
+		{name: "タスクが終わる前にctxが終了すれば残ったタスクの数とctxのエラーを返す", release: false, want: 1, wantError: context.Canceled},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			started := make(chan struct{})
+			release := make(chan struct{})
+			runErr := make(chan error, 1)
+			clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+			timer := new(Timer).SetClock(clock)
+			go func() {
+				runErr <- timer.Run(context.Background(), time.Hour, func() {
+					close(started)
+					<-release
+				})
+			}()
+			clock.BlockUntil(1)
+			clock.Advance(15 * time.Hour)
+			<-started
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if test.release {
+				close(release)
+			} else {
+				cancel()
+				defer close(release)
+			}
+			defer cancel()
+			got, err := timer.Shutdown(ctx)
+			if test.want != got || !errors.Is(err, test.wantError) || <-runErr != nil {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.want, test.wantError, got, err)
+			}
+		})
+	}
+}
+
+// blockingTickObserver - OnTickで止まるObserver
+type blockingTickObserver struct {
+	NopObserver
+	ticked  chan struct{}
+	release chan struct{}
+}
+
+func (o *blockingTickObserver) OnTick(time.Time) {
+	close(o.ticked)
+	<-o.release
+}
+
+func Test_Timer_Shutdown_Dispatching(t *testing.T) {
+	t.Parallel()
+	observer := &blockingTickObserver{ticked: make(chan struct{}), release: make(chan struct{})}
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).SetObserver(observer)
+	var mtx sync.Mutex
+	runs := 0
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- timer.Run(context.Background(), time.Hour, func() {
+			mtx.Lock()
+			defer mtx.Unlock()
+			runs++
+		})
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(15 * time.Hour)
+	<-observer.ticked
+
+	// 実行時刻が来て実行枠を取る前にShutdownが返っても、その後でタスクは実行されない
+	got, err := timer.Shutdown(context.Background())
+	close(observer.release)
+	if err := <-runErr; err != nil {
+		t.Fatal(err)
+	}
+	triggered, triggerErr := timer.Trigger()
+	if _, err := timer.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	mtx.Lock()
+	defer mtx.Unlock()
+	want := []interface{}{0, nil, 0, false, TimerNotRunningError}
+	results := []interface{}{got, err, runs, triggered, triggerErr}
+	if !reflect.DeepEqual(want, results) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, results)
+	}
+}
+
+func Test_Timer_Wait(t *testing.T) {
+	t.Parallel()
+	got, err := new(Timer).Wait(context.Background())
+	if got != 0 || err != nil {
+		t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), 0, nil, got, err)
+	}
+}
+
+func Test_Timer_SetTaskTimeout(t *testing.T) {
+	t.Parallel()
+	errCh := make(chan error, 1)
+	ctxErr := make(chan error, 1)
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).SetTaskTimeout(10 * time.Second).SetErrorHandler(func(err error) { errCh <- err })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = timer.RunContext(ctx, time.Hour, func(ctx context.Context) error {
+			<-ctx.Done()
+			ctxErr <- ctx.Err()
+			return ctx.Err()
+		})
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(15 * time.Hour)
+	clock.BlockUntil(2) // 次の実行とタイムアウトのタイマー
+	clock.Advance(10 * time.Second)
+	got1, got2 := <-ctxErr, <-errCh
+	if got1 != context.DeadlineExceeded || !errors.Is(got2, TimerTaskTimeoutError) {
+		t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), context.DeadlineExceeded, TimerTaskTimeoutError, got1, got2)
+	}
+}
+
+func Test_Timer_SetTimeoutPolicy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		policy TimeoutPolicy
+		want   int
+	}{
+		{name: "TimeoutPolicyHoldならタイムアウトしてもタスクが終わるまで実行中のまま", policy: TimeoutPolicyHold, want: 1},
+		{name: "TimeoutPolicyReleaseならタイムアウトした時点で実行中でなくなる", policy: TimeoutPolicyRelease, want: 0},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			started := make(chan struct{})
+			release := make(chan struct{})
+			defer close(release)
+			timedOut := make(chan struct{})
+			clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+			timer := new(Timer).SetClock(clock).SetTaskTimeout(10 * time.Second).SetTimeoutPolicy(test.policy)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				_ = timer.RunContext(ctx, time.Hour, func(ctx context.Context) error {
+					close(started)
+					<-ctx.Done()
+					close(timedOut)
+					<-release // ctxを無視して動き続けるタスク
+					return nil
+				})
+			}()
+
+			clock.BlockUntil(1)
+			clock.Advance(15 * time.Hour)
+			<-started
+			clock.BlockUntil(2)
+			clock.Advance(10 * time.Second)
+			<-timedOut
+
+			var got int
+			for i := 0; i < 100; i++ { // 実行枠の解放は非同期なので少し待つ
+				waitCtx, waitCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				got, _ = timer.Wait(waitCtx)
+				waitCancel()
+				if got == test.want {
+					break
+				}
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Timer_execute_Panic(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		panicHandler bool
+		errorHandler bool
+		wantPanic    int
+		wantError    int
+	}{
+		{name: "パニックハンドラがあればパニックハンドラに渡す", panicHandler: true, errorHandler: true, wantPanic: 1, wantError: 0},
+		{name: "パニックハンドラがなければエラーハンドラに渡す", panicHandler: false, errorHandler: true, wantPanic: 0, wantError: 1},
+		{name: "どちらもなくてもpanicしない", panicHandler: false, errorHandler: false, wantPanic: 0, wantError: 0},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var gotPanic, gotError int
+			timer := &Timer{taskRunning: 1}
+			if test.panicHandler {
+				timer.SetPanicHandler(func(err *PanicError) {
+					if err.Value == "boom" && len(err.Stack) > 0 {
+						gotPanic++
+					}
+				})
+			}
+			if test.errorHandler {
+				timer.SetErrorHandler(func(err error) {
+					var pe *PanicError
+					if errors.As(err, &pe) && pe.Value == "boom" {
+						gotError++
+					}
+				})
+			}
+			timer.execute(context.Background(), time.Time{}, func(context.Context) error { panic("boom") })
+			if test.wantPanic != gotPanic || test.wantError != gotError || timer.taskRunning != 0 {
+				t.Errorf("%s error\nwant: %+v, %+v, %+v\ngot: %+v, %+v, %+v\n", t.Name(), test.wantPanic, test.wantError, 0, gotPanic, gotError, timer.taskRunning)
+			}
+		})
+	}
+}
+
+func Test_Timer_SetCrashOnPanic(t *testing.T) {
+	t.Parallel()
+	var reported bool
+	timer := (&Timer{taskRunning: 1}).SetCrashOnPanic(true).SetPanicHandler(func(*PanicError) { reported = true })
+	defer func() {
+		got := recover()
+		if got != "boom" || !reported || timer.taskRunning != 0 {
+			t.Errorf("%s error\nwant: %+v, %+v, %+v\ngot: %+v, %+v, %+v\n", t.Name(), "boom", true, 0, got, reported, timer.taskRunning)
+		}
+	}()
+	timer.execute(context.Background(), time.Time{}, func(context.Context) error { panic("boom") })
+}
+
+func Test_Timer_Run_Panic(t *testing.T) {
+	t.Parallel()
+	panicCh := make(chan *PanicError, 2)
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).SetPanicHandler(func(err *PanicError) { panicCh <- err })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = timer.Run(ctx, time.Hour, func() { panic("boom") }) }()
+
+	for _, w := range []time.Time{time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local), time.Date(2021, 1, 5, 1, 0, 0, 0, time.Local)} {
+		clock.BlockUntil(1)
+		clock.Set(w)
+		if got := <-panicCh; got.Value != "boom" {
+			t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), "boom", got.Value)
+		}
+	}
+}
+
+func Test_Timer_SetOverlapPolicy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		timerRunning bool
+		policy       OverlapPolicy
+		want1        OverlapPolicy
+		want2        bool
+	}{
+		{name: "timerRunningでなければ設定が反映される", policy: OverlapQueue, want1: OverlapQueue, want2: false},
+		{name: "OverlapParallelなら多重実行を許容する", policy: OverlapParallel, want1: OverlapParallel, want2: true},
+		{name: "timerRunningであれば設定が反映されない", timerRunning: true, policy: OverlapParallel, want1: OverlapSkip, want2: false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			timer := &Timer{timerRunning: test.timerRunning}
+			timer.SetOverlapPolicy(test.policy)
+			if !reflect.DeepEqual(test.want1, timer.overlapPolicy) || !reflect.DeepEqual(test.want2, timer.parallelRunnable) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.want1, test.want2, timer.overlapPolicy, timer.parallelRunnable)
+			}
+		})
+	}
+}
+
+func Test_Timer_acquireTaskRunning(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		policy       OverlapPolicy
+		taskRunning  int
+		pending      int
+		wantAcquired bool
+		wantSkipped  bool
+		wantReason   SkipReason
+		wantRunning  int
+		wantPending  int
+	}{
+		{name: "実行中のタスクがなければ実行枠が取れる",
+			policy: OverlapSkip, taskRunning: 0, wantAcquired: true, wantRunning: 1},
+		{name: "OverlapSkipで実行中なら実行しない",
+			policy: OverlapSkip, taskRunning: 1, wantSkipped: true, wantReason: SkipReasonOverlap, wantRunning: 1},
+		{name: "OverlapQueueで実行中なら溜める",
+			policy: OverlapQueue, taskRunning: 1, pending: 1, wantRunning: 1, wantPending: 2},
+		{name: "OverlapCoalesceで実行中なら1回だけ溜める",
+			policy: OverlapCoalesce, taskRunning: 1, pending: 0, wantRunning: 1, wantPending: 1},
+		{name: "OverlapCoalesceで実行中かつ溜まっていればまとめる",
+			policy: OverlapCoalesce, taskRunning: 1, pending: 1, wantSkipped: true, wantReason: SkipReasonCoalesced, wantRunning: 1, wantPending: 1},
+		{name: "OverlapParallelなら実行中でも実行枠が取れる",
+			policy: OverlapParallel, taskRunning: 1, wantAcquired: true, wantRunning: 2},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			timer := (&Timer{}).SetOverlapPolicy(test.policy)
+			timer.taskRunning, timer.pending = test.taskRunning, make([]time.Time, test.pending)
+			acquired, skipped, reason := timer.acquireTaskRunning(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+			if test.wantAcquired != acquired || test.wantSkipped != skipped || test.wantReason != reason ||
+				test.wantRunning != timer.taskRunning || test.wantPending != len(timer.pending) {
+				t.Errorf("%s error\nwant: %+v, %+v, %+v, %+v, %+v\ngot: %+v, %+v, %+v, %+v, %+v\n", t.Name(),
+					test.wantAcquired, test.wantSkipped, test.wantReason, test.wantRunning, test.wantPending,
+					acquired, skipped, reason, timer.taskRunning, len(timer.pending))
+			}
+		})
+	}
+}
+
+func Test_Timer_decrementTaskRunning(t *testing.T) {
+	t.Parallel()
+	scheduled := time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		name        string
+		runCtx      context.Context
+		stopping    bool
+		pending     []time.Time
+		wantOK      bool
+		wantRunning int
+		wantPending int
+	}{
+		{name: "溜まっていなければカウントを減らす", runCtx: context.Background(), wantRunning: 0},
+		{name: "溜まっていれば実行枠を引き継ぐ",
+			runCtx: context.Background(), pending: []time.Time{scheduled, scheduled}, wantOK: true, wantRunning: 1, wantPending: 1},
+		{name: "Shutdownで止めていれば溜まっている実行を捨てる",
+			runCtx: context.Background(), stopping: true, pending: []time.Time{scheduled}, wantRunning: 0},
+		{name: "タイマーの実行が終わっていれば溜まっている実行を捨てる",
+			pending: []time.Time{scheduled}, wantRunning: 0},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			timer := &Timer{taskRunning: 1, runCtx: test.runCtx, stopping: test.stopping, pending: test.pending}
+			ctx, next, ok := timer.decrementTaskRunning()
+			if test.wantOK != ok || test.wantRunning != timer.taskRunning || test.wantPending != len(timer.pending) ||
+				(ok && (ctx != test.runCtx || !next.Equal(scheduled))) {
+				t.Errorf("%s error\nwant: %+v, %+v, %+v\ngot: %+v, %+v, %+v\n", t.Name(),
+					test.wantOK, test.wantRunning, test.wantPending, ok, timer.taskRunning, len(timer.pending))
+			}
+		})
+	}
+}
+
+func Test_Timer_Run_Overlap(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		policy   OverlapPolicy
+		wantRuns int
+		want     []SkipReason
+	}{
+		{name: "OverlapSkipなら実行中に来た実行時刻はスキップされる", policy: OverlapSkip, wantRuns: 1,
+			want: []SkipReason{SkipReasonOverlap, SkipReasonOverlap, SkipReasonOverlap}},
+		{name: "OverlapQueueなら実行中に来た回数だけ後で実行される", policy: OverlapQueue, wantRuns: 4, want: nil},
+		{name: "OverlapCoalesceなら実行中に何回来ても後で1回だけ実行される", policy: OverlapCoalesce, wantRuns: 2,
+			want: []SkipReason{SkipReasonCoalesced, SkipReasonCoalesced}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var mtx sync.Mutex
+			var runs int
+			var got []SkipReason
+			release := make(chan struct{})
+			clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+			timer := new(Timer).SetClock(clock).SetOverlapPolicy(test.policy).
+				SetSkipHandler(func(_ time.Time, reason SkipReason) { got = append(got, reason) })
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				_ = timer.Run(ctx, time.Hour, func() {
+					mtx.Lock()
+					runs++
+					first := runs == 1
+					mtx.Unlock()
+					if first {
+						<-release
+					}
+				})
+			}()
+
+			for h := 0; h < 4; h++ {
+				clock.BlockUntil(1)
+				clock.Set(time.Date(2021, 1, 5, h, 0, 0, 0, time.Local))
+			}
+			clock.BlockUntil(1) // 最後の実行時刻の処理が終わるのを待つ
+			close(release)
+			if _, err := timer.Wait(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			mtx.Lock()
+			defer mtx.Unlock()
+			if test.wantRuns != runs || !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.wantRuns, test.want, runs, got)
+			}
+		})
+	}
+}
+
+func Test_Timer_tryIncrementTaskRunning(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		maxConcurrency   int
+		parallelRunnable bool
+		taskRunning      int
+		limiter          *Limiter
+		want1            bool
+		want2            SkipReason
+		want3            int
+	}{
+		{name: "上限未満なら増やせる",
+			maxConcurrency: 3, taskRunning: 2, want1: true, want3: 3},
+		{name: "上限に達していれば増やせない",
+			maxConcurrency: 3, taskRunning: 3, want1: false, want2: SkipReasonOverlap, want3: 3},
+		{name: "多重起動可でも上限に達していれば増やせない",
+			maxConcurrency: 3, parallelRunnable: true, taskRunning: 3, want1: false, want2: SkipReasonOverlap, want3: 3},
+		{name: "上限未満でもLimiterの上限に達していれば増やせない",
+			maxConcurrency: 3, taskRunning: 1, limiter: &Limiter{max: 2, running: 2}, want1: false, want2: SkipReasonLimiter, want3: 1},
+		{name: "Limiterの上限に達していなければ増やせる",
+			parallelRunnable: true, taskRunning: 1, limiter: &Limiter{max: 2, running: 1}, want1: true, want3: 2},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			timer := &Timer{maxConcurrency: test.maxConcurrency, parallelRunnable: test.parallelRunnable, taskRunning: test.taskRunning, limiter: test.limiter}
+			got1, got2 := timer.tryIncrementTaskRunning()
+			if !reflect.DeepEqual(test.want1, got1) || !reflect.DeepEqual(test.want2, got2) || !reflect.DeepEqual(test.want3, timer.taskRunning) {
+				t.Errorf("%s error\nwant: %+v, %+v, %+v\ngot: %+v, %+v, %+v\n", t.Name(), test.want1, test.want2, test.want3, got1, got2, timer.taskRunning)
+			}
+		})
+	}
+}
+
+func Test_Timer_Run_Limiter(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	started := make(chan struct{}, 10)
+	limiter := NewLimiter(1)
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	timer1 := new(Timer).SetClock(clock).SetLimiter(limiter).SetOverlapPolicy(OverlapQueue)
+	timer2 := new(Timer).SetClock(clock).SetLimiter(limiter).SetOverlapPolicy(OverlapQueue)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	task := func() {
+		started <- struct{}{}
+		<-release
+	}
+	go func() { _ = timer1.Run(ctx, time.Hour, task) }()
+	go func() { _ = timer2.Run(ctx, time.Hour, task) }()
+
+	clock.BlockUntil(2)
+	clock.Set(time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local))
+	clock.BlockUntil(2)
+	close(release)
+	if _, err := timer1.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := timer2.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want1, want2 := 1, 1
+	got1, got2 := len(started), timer1.RejectedCount()+timer2.RejectedCount()
+	if want1 != got1 || want2 != got2 || limiter.Running() != 0 {
+		t.Errorf("%s error\nwant: %+v, %+v, %+v\ngot: %+v, %+v, %+v\n", t.Name(), want1, want2, 0, got1, got2, limiter.Running())
+	}
+}
+
+func Test_Timer_SetMaxConcurrency(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		timerRunning bool
+		want         int
+	}{
+		{name: "timerRunningでなければ設定が反映される", timerRunning: false, want: 5},
+		{name: "timerRunningであれば設定が反映されない", timerRunning: true, want: 0},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			timer := &Timer{timerRunning: test.timerRunning}
+			timer.SetMaxConcurrency(5)
+			got := timer.maxConcurrency
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Timer_UpdateTerms(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		terms     []Term
+		want      []Term
+		wantError error
+	}{
+		{name: "termsが空ならerrorで変更されない",
+			terms:     []Term{},
+			want:      []Term{NewTerm(NewTime(8, 45, 0), NewTime(15, 15, 0))},
+			wantError: TimerNoTermsError},
+		{name: "重複を除いて並び替えた期間で置き換えられる",
+			terms: []Term{
+				NewTerm(NewTime(16, 30, 0), NewTime(5, 30, 0)),
+				NewTerm(NewTime(9, 0, 0), NewTime(11, 30, 0)),
+				NewTerm(NewTime(16, 30, 0), NewTime(5, 30, 0)),
+			},
+			want: []Term{NewTerm(NewTime(9, 0, 0), NewTime(11, 30, 0)), NewTerm(NewTime(16, 30, 0), NewTime(5, 30, 0))}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			timer := &Timer{timerRunning: true, terms: []Term{NewTerm(NewTime(8, 45, 0), NewTime(15, 15, 0))}}
+			err := timer.UpdateTerms(test.terms...)
+			if !reflect.DeepEqual(test.want, timer.terms) || !reflect.DeepEqual(test.wantError, err) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.want, test.wantError, timer.terms, err)
+			}
+		})
+	}
+}
+
+func Test_Timer_RemoveTerm(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		terms     []Term
+		term      Term
+		want      []Term
+		wantError error
+	}{
+		{name: "期間が見つからなければerror",
+			terms:     []Term{NewTerm(NewTime(8, 45, 0), NewTime(15, 15, 0))},
+			term:      NewTerm(NewTime(9, 0, 0), NewTime(15, 0, 0)),
+			want:      []Term{NewTerm(NewTime(8, 45, 0), NewTime(15, 15, 0))},
+			wantError: TimerTermNotFoundError},
+		{name: "最後の期間は削除できない",
+			terms:     []Term{NewTerm(NewTime(8, 45, 0), NewTime(15, 15, 0))},
+			term:      NewTerm(NewTime(8, 45, 0), NewTime(15, 15, 0)),
+			want:      []Term{NewTerm(NewTime(8, 45, 0), NewTime(15, 15, 0))},
+			wantError: TimerNoTermsError},
+		{name: "一致する期間が削除される",
+			terms:     []Term{NewTerm(NewTime(8, 45, 0), NewTime(15, 15, 0)), NewTerm(NewTime(16, 30, 0), NewTime(5, 30, 0))},
+			term:      NewTerm(NewTime(8, 45, 0), NewTime(15, 15, 0)),
+			want:      []Term{NewTerm(NewTime(16, 30, 0), NewTime(5, 30, 0))},
+			wantError: nil},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			timer := &Timer{timerRunning: true, terms: test.terms}
+			err := timer.RemoveTerm(test.term)
+			if !reflect.DeepEqual(test.want, timer.terms) || !reflect.DeepEqual(test.wantError, err) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.want, test.wantError, timer.terms, err)
+			}
+		})
+	}
+}
+
+func Test_Timer_SetInterval(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		interval  time.Duration
+		want      time.Duration
+		wantError error
+	}{
+		{name: "intervalが1未満ならerrorで変更されない", interval: 0, want: time.Minute, wantError: TimerNotSetIntervalError},
+		{name: "実行中でも変更される", interval: time.Hour, want: time.Hour, wantError: nil},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			timer := &Timer{timerRunning: true, interval: time.Minute}
+			err := timer.SetInterval(test.interval)
+			if !reflect.DeepEqual(test.want, timer.interval) || !reflect.DeepEqual(test.wantError, err) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.want, test.wantError, timer.interval, err)
+			}
+		})
+	}
+}
+
+func Test_Timer_Run_SetInterval(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		interval time.Duration
+	}{
+		{name: "実行前にSetIntervalで設定すれば、Runのintervalより優先される", interval: time.Minute},
+		{name: "実行前にSetIntervalで設定すれば、Runのintervalは0でもよい", interval: 0},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			clock := NewFakeClock(time.Date(2021, 1, 4, 8, 0, 0, 0, time.Local))
+			timer := new(Timer).SetClock(clock).SetStartNow(true)
+			if err := timer.SetInterval(time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			ch := make(chan time.Time, 10)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			runErr := make(chan error, 1)
+			go func() { runErr <- timer.Run(ctx, test.interval, func() { ch <- clock.Now() }) }()
+
+			got := []time.Time{<-ch}
+			clock.BlockUntil(1)
+			got = append(got, timer.NextRun())
+			want := []time.Time{time.Date(2021, 1, 4, 8, 0, 0, 0, time.Local), time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local)}
+			if !reflect.DeepEqual(want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+			}
+			cancel()
+			if err := <-runErr; err != nil {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), nil, err)
+			}
+		})
+	}
+}
+
+func Test_Timer_Run_Reconfigure(t *testing.T) {
+	t.Parallel()
+	clock := NewFakeClock(time.Date(2021, 1, 4, 8, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).AddTerm(NewTerm(NewTime(9, 0, 0), NewTime(15, 0, 0)))
+	ch := make(chan time.Time, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = timer.Run(ctx, time.Minute, func() { ch <- clock.Now() }) }()
+
+	steps := []struct {
+		reconfigure func()
+		now         time.Time
+	}{
+		{now: time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local)},
+		{now: time.Date(2021, 1, 4, 9, 1, 0, 0, time.Local),
+			reconfigure: func() { _ = timer.SetInterval(time.Hour) }}, // 待っている実行時刻は変わらない
+		{now: time.Date(2021, 1, 4, 10, 1, 0, 0, time.Local),
+			reconfigure: func() { _ = timer.UpdateTerms(NewTerm(NewTime(12, 0, 0), NewTime(13, 0, 0))) }},
+		{now: time.Date(2021, 1, 4, 12, 0, 0, 0, time.Local)},
+	}
+	var want, got []time.Time
+	for _, step := range steps {
+		clock.BlockUntil(1)
+		if step.reconfigure != nil {
+			step.reconfigure()
+		}
+		clock.Set(step.now)
+		want = append(want, step.now)
+		got = append(got, <-ch)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Timer_Run_Reconfigure_Concurrent(t *testing.T) {
+	t.Parallel()
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = timer.Run(ctx, time.Minute, func() {}) }()
+
+	// 次回実行日時を決めている間に期間を変えても、データ競合にならない
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			_ = timer.UpdateTerms(NewTerm(NewTime(0, 0, 0), NewTime(23, 59, i%60)))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+	}
+	<-done
+}
+
+func Test_Timer_SetResumePolicy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		timerRunning bool
+		want         ResumePolicy
+	}{
+		{name: "timerRunningでなければ設定が反映される", timerRunning: false, want: ResumeRealign},
+		{name: "timerRunningであれば設定が反映されない", timerRunning: true, want: ResumeSkip},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			timer := &Timer{timerRunning: test.timerRunning}
+			timer.SetResumePolicy(ResumeRealign)
+			got := timer.resumePolicy
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Timer_Pause(t *testing.T) {
+	t.Parallel()
+	timer := new(Timer)
+	timer.Pause()
+	acquired, skipped, reason := timer.acquireTaskRunning(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	want := []interface{}{true, false, true, SkipReasonPaused, 0, 1, 0}
+	got := []interface{}{timer.IsPaused(), acquired, skipped, reason, timer.taskRunning, timer.missed, timer.RejectedCount()}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+
+	timer.Resume()
+	want = []interface{}{false, 0}
+	got = []interface{}{timer.IsPaused(), timer.missed}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Timer_Run_PauseResume(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		policy ResumePolicy
+		want   []time.Time
+	}{
+		{name: "ResumeSkipなら一時停止中の実行時刻は捨てられる", policy: ResumeSkip,
+			want: []time.Time{time.Date(2021, 1, 5, 1, 0, 0, 0, time.Local)}},
+		{name: "ResumeRunOnceなら再開時に1回だけ実行される", policy: ResumeRunOnce,
+			want: []time.Time{time.Date(2021, 1, 5, 0, 30, 0, 0, time.Local), time.Date(2021, 1, 5, 1, 0, 0, 0, time.Local)}},
+		{name: "ResumeRealignなら再開時に実行され、そこからintervalごとに実行される", policy: ResumeRealign,
+			want: []time.Time{time.Date(2021, 1, 5, 0, 30, 0, 0, time.Local), time.Date(2021, 1, 5, 1, 30, 0, 0, time.Local)}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var skips []SkipReason
+			clock := NewFakeClock(time.Date(2021, 1, 4, 23, 30, 0, 0, time.Local))
+			timer := new(Timer).SetClock(clock).SetResumePolicy(test.policy).
+				SetSkipHandler(func(_ time.Time, reason SkipReason) { skips = append(skips, reason) })
+			ch := make(chan time.Time, 10)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() { _ = timer.Run(ctx, time.Hour, func() { ch <- clock.Now() }) }()
+
+			clock.BlockUntil(1)
+			timer.Pause()
+			clock.Set(time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local))
+			clock.BlockUntil(1)
+			clock.Set(time.Date(2021, 1, 5, 0, 30, 0, 0, time.Local))
+			timer.Resume()
+
+			var got []time.Time
+			for _, want := range test.want {
+				if clock.Now().Before(want) {
+					clock.BlockUntil(1)
+					clock.Set(want)
+				}
+				got = append(got, <-ch)
+			}
+			if !reflect.DeepEqual(test.want, got) || !reflect.DeepEqual([]SkipReason{SkipReasonPaused}, skips) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.want, []SkipReason{SkipReasonPaused}, got, skips)
+			}
+		})
+	}
+}
+
+func Test_Timer_Trigger(t *testing.T) {
+	t.Parallel()
+	timer := new(Timer)
+	got1, got2 := timer.Trigger()
+	if !reflect.DeepEqual(false, got1) || !errors.Is(got2, TimerNotRunningError) {
+		t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), false, TimerNotRunningError, got1, got2)
+	}
+}
+
+func Test_Timer_TriggerContext(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		want1 bool
+		want2 error
+	}{
+		{name: "ctxがnilならTimerNotSetContextError", ctx: nil, want1: false, want2: TimerNotSetContextError},
+		{name: "実行中でなければTimerNotRunningError", ctx: context.Background(), want1: false, want2: TimerNotRunningError},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got1, got2 := new(Timer).TriggerContext(test.ctx)
+			if !reflect.DeepEqual(test.want1, got1) || !errors.Is(got2, test.want2) {
+				t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), test.want1, test.want2, got1, got2)
+			}
+		})
+	}
+}
+
+func Test_Timer_Run_Trigger(t *testing.T) {
+	t.Parallel()
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock)
+	ch := make(chan time.Time, 10)
+	release := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = timer.Run(ctx, time.Hour, func() {
+			ch <- clock.Now()
+			<-release
+		})
+	}()
+	clock.BlockUntil(1)
+
+	var got []interface{}
+	accepted, err := timer.Trigger()
+	got = append(got, accepted, err, <-ch)
+	accepted, err = timer.Trigger() // 実行中なので実行されない
+	got = append(got, accepted, err)
+	close(release)
+	if _, err := timer.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	clock.Set(time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local)) // 次回実行日時は変わっていない
+	got = append(got, <-ch)
+
+	want := []interface{}{
+		true, nil, time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local),
+		false, nil,
+		time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local)}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Timer_Run_Trigger_Queue(t *testing.T) {
+	t.Parallel()
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).SetOverlapPolicy(OverlapQueue)
+	errs := make(chan error, 10)
+	release := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = timer.RunContext(ctx, time.Hour, func(ctx context.Context) error {
+			errs <- ctx.Err()
+			<-release
+			return nil
+		})
+	}()
+	clock.BlockUntil(1)
+
+	triggerCtx, triggerCancel := context.WithCancel(context.Background())
+	if _, err := timer.TriggerContext(triggerCtx); err != nil {
+		t.Fatal(err)
+	}
+	got := []error{<-errs}
+	clock.Set(time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local)) // 実行中なので溜まる
+	clock.BlockUntil(1)
+
+	// Triggerで渡したctxが終了しても、溜まっていた実行はタイマーの実行に渡されたctxで実行される
+	triggerCancel()
+	release <- struct{}{}
+	got = append(got, <-errs)
+	release <- struct{}{}
+	if _, err := timer.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []error{nil, nil}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Timer_NextRuns(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		timer *Timer
+		from  time.Time
+		n     int
+		want  []time.Time
+	}{
+		{name: "intervalもスケジュールもなければnil", timer: &Timer{}, from: time.Date(2021, 1, 4, 8, 0, 0, 0, time.Local), n: 3, want: nil},
+		{name: "nが0以下ならnil", timer: &Timer{interval: time.Hour}, from: time.Date(2021, 1, 4, 8, 0, 0, 0, time.Local), n: 0, want: nil},
+		{name: "期間がなければ翌日の0時からintervalごと",
+			timer: &Timer{interval: 12 * time.Hour}, from: time.Date(2021, 1, 4, 8, 0, 0, 0, time.Local), n: 3,
+			want: []time.Time{
+				time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local),
+				time.Date(2021, 1, 5, 12, 0, 0, 0, time.Local),
+				time.Date(2021, 1, 6, 0, 0, 0, 0, time.Local)}},
+		{name: "期間を過ぎたら次の開始時刻から",
+			timer: &Timer{interval: 30 * time.Minute, terms: []Term{NewTerm(NewTime(9, 0, 0), NewTime(10, 0, 0))}},
+			from:  time.Date(2021, 1, 4, 8, 0, 0, 0, time.Local), n: 4,
+			want: []time.Time{
+				time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local),
+				time.Date(2021, 1, 4, 9, 30, 0, 0, time.Local),
+				time.Date(2021, 1, 4, 10, 0, 0, 0, time.Local),
+				time.Date(2021, 1, 5, 9, 0, 0, 0, time.Local)}},
+		{name: "startNowで実行可能ならfromから",
+			timer: &Timer{interval: time.Hour, startNow: true, terms: []Term{NewTerm(NewTime(9, 0, 0), NewTime(10, 0, 0))}},
+			from:  time.Date(2021, 1, 4, 9, 15, 0, 0, time.Local), n: 2,
+			want: []time.Time{
+				time.Date(2021, 1, 4, 9, 15, 0, 0, time.Local),
+				time.Date(2021, 1, 5, 9, 0, 0, 0, time.Local)}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := test.timer.NextRuns(test.from, test.n)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Timer_NextRuns_Running(t *testing.T) {
+	t.Parallel()
+	clock := NewFakeClock(time.Date(2021, 1, 4, 8, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).SetStartNow(true).AddTerm(NewTerm(NewTime(9, 0, 0), NewTime(15, 0, 0)))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = timer.Run(ctx, time.Hour, func() {}) }()
+	clock.BlockUntil(1)
+	clock.Set(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	clock.BlockUntil(1)
+	clock.Set(time.Date(2021, 1, 4, 9, 30, 0, 0, time.Local))
+
+	// 実行中なら、startNowによらず待っている実行日時から続く
+	got := timer.NextRuns(clock.Now(), 3)
+	want := []time.Time{
+		time.Date(2021, 1, 4, 10, 0, 0, 0, time.Local),
+		time.Date(2021, 1, 4, 11, 0, 0, 0, time.Local),
+		time.Date(2021, 1, 4, 12, 0, 0, 0, time.Local)}
+	if !reflect.DeepEqual(want, got) || !reflect.DeepEqual(want[0], timer.NextRun()) {
+		t.Errorf("%s error\nwant: %+v, %+v\ngot: %+v, %+v\n", t.Name(), want, want[0], got, timer.NextRun())
+	}
+
+	// fromが待っている実行日時より後なら、from以降の実行日時を返す
+	got = timer.NextRuns(time.Date(2021, 1, 4, 14, 30, 0, 0, time.Local), 2)
+	want = []time.Time{
+		time.Date(2021, 1, 4, 15, 0, 0, 0, time.Local),
+		time.Date(2021, 1, 5, 9, 0, 0, 0, time.Local)}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Timer_NextRun(t *testing.T) {
+	t.Parallel()
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).AddTerm(NewTerm(NewTime(9, 30, 0), NewTime(15, 0, 0)))
+	_ = timer.SetInterval(time.Hour)
+	got := []time.Time{timer.NextRun()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = timer.Run(ctx, time.Hour, func() {}) }()
+	clock.BlockUntil(1)
+	clock.Set(time.Date(2021, 1, 4, 9, 30, 0, 0, time.Local))
+	clock.BlockUntil(1)
+	got = append(got, timer.NextRun())
+
+	want := []time.Time{time.Date(2021, 1, 4, 9, 30, 0, 0, time.Local), time.Date(2021, 1, 4, 10, 30, 0, 0, time.Local)}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Timer_SetHistorySize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		timerRunning bool
+		size         int
+		want         int
+	}{
+		{name: "timerRunningでなければ設定が反映される", size: 5, want: 5},
+		{name: "0以下なら記録しない", size: 0, want: -1},
+		{name: "timerRunningであれば設定が反映されない", timerRunning: true, size: 5, want: -1},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			timer := &Timer{timerRunning: test.timerRunning}
+			timer.SetHistorySize(test.size)
+			got := -1
+			if timer.history != nil {
+				got = cap(timer.history.records)
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Timer_Run_History(t *testing.T) {
+	t.Parallel()
+	taskErr := errors.New("task error")
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).SetHistorySize(4)
+	started := make(chan struct{}, 10)
+	results := make(chan error)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = timer.RunContext(ctx, time.Hour, func(context.Context) error {
+			started <- struct{}{}
+			return <-results
+		})
+	}()
+
+	clock.BlockUntil(1)
+	clock.Set(time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local))
+	<-started
+	clock.BlockUntil(1)
+	clock.Set(time.Date(2021, 1, 5, 1, 0, 0, 0, time.Local)) // 実行中なのでスキップ
+	clock.BlockUntil(1)
+	results <- nil
+	if _, err := timer.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	clock.Set(time.Date(2021, 1, 5, 2, 0, 0, 0, time.Local))
+	results <- taskErr
+	if _, err := timer.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	clock.BlockUntil(1)
+	clock.Set(time.Date(2021, 1, 5, 3, 0, 0, 0, time.Local))
+	results <- nil
+	if _, err := timer.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// 終わった順に記録される
+	want := []Execution{
+		{Scheduled: time.Date(2021, 1, 5, 1, 0, 0, 0, time.Local), Outcome: OutcomeSkipped, Reason: SkipReasonOverlap},
+		{Scheduled: time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local), Start: time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local),
+			End: time.Date(2021, 1, 5, 1, 0, 0, 0, time.Local), Outcome: OutcomeSuccess},
+		{Scheduled: time.Date(2021, 1, 5, 2, 0, 0, 0, time.Local), Start: time.Date(2021, 1, 5, 2, 0, 0, 0, time.Local),
+			End: time.Date(2021, 1, 5, 2, 0, 0, 0, time.Local), Outcome: OutcomeError, Err: taskErr},
+		{Scheduled: time.Date(2021, 1, 5, 3, 0, 0, 0, time.Local), Start: time.Date(2021, 1, 5, 3, 0, 0, 0, time.Local),
+			End: time.Date(2021, 1, 5, 3, 0, 0, 0, time.Local), Outcome: OutcomeSuccess},
+	}
+	got := timer.History()
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+// testLogger - 時刻を除いたテキスト形式で、bufにログを残すロガーを返す
+func testLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func Test_Timer_Run_History_Queue(t *testing.T) {
+	t.Parallel()
+	clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+	timer := new(Timer).SetClock(clock).SetOverlapPolicy(OverlapQueue).SetHistorySize(3)
+	started := make(chan struct{}, 10)
+	results := make(chan error)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = timer.RunContext(ctx, time.Hour, func(context.Context) error {
+			started <- struct{}{}
+			return <-results
+		})
+	}()
+
+	clock.BlockUntil(1)
+	clock.Set(time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local))
+	<-started
+	for _, now := range []time.Time{
+		time.Date(2021, 1, 5, 1, 0, 0, 0, time.Local), // 実行中なので溜まる
+		time.Date(2021, 1, 5, 2, 0, 0, 0, time.Local), // 実行中なので溜まる
+		time.Date(2021, 1, 5, 2, 30, 0, 0, time.Local),
+	} {
+		clock.BlockUntil(1)
+		clock.Set(now)
+	}
+	results <- nil
+	<-started
+	results <- nil
+	<-started
+	results <- nil
+	if _, err := timer.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// 溜まっていた実行も、実行時刻が来た日時を予定日時として記録される
+	want := []Execution{
+		{Scheduled: time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local), Start: time.Date(2021, 1, 5, 0, 0, 0, 0, time.Local),
+			End: time.Date(2021, 1, 5, 2, 30, 0, 0, time.Local), Outcome: OutcomeSuccess},
+		{Scheduled: time.Date(2021, 1, 5, 1, 0, 0, 0, time.Local), Start: time.Date(2021, 1, 5, 2, 30, 0, 0, time.Local),
+			End: time.Date(2021, 1, 5, 2, 30, 0, 0, time.Local), Outcome: OutcomeSuccess},
+		{Scheduled: time.Date(2021, 1, 5, 2, 0, 0, 0, time.Local), Start: time.Date(2021, 1, 5, 2, 30, 0, 0, time.Local),
+			End: time.Date(2021, 1, 5, 2, 30, 0, 0, time.Local), Outcome: OutcomeSuccess},
+	}
+	got := timer.History()
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Timer_SetLogger(t *testing.T) {
+	t.Parallel()
+	logger := slog.Default()
+	tests := []struct {
+		name         string
+		timerRunning bool
+		want         *slog.Logger
+	}{
+		{name: "timerRunningでなければ設定が反映される", timerRunning: false, want: logger},
+		{name: "timerRunningであれば設定が反映されない", timerRunning: true, want: nil},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			timer := &Timer{timerRunning: test.timerRunning}
+			timer.SetLogger(logger)
+			got := timer.logger
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+// callbackHandler - 記録を受け取るたびにcallbackを呼ぶslog.Handler
+type callbackHandler struct {
+	callback func()
+}
+
+func (h callbackHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h callbackHandler) Handle(context.Context, slog.Record) error {
+	h.callback()
+	return nil
+}
+func (h callbackHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h callbackHandler) WithGroup(string) slog.Handler      { return h }
+
+func Test_Timer_Run_LoggerCallback(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		run  func(ctx context.Context, clock *FakeClock, timer *Timer)
+	}{
+		{name: "Timerでロガーのハンドラからタイマーを操作しても止まらない",
+			run: func(ctx context.Context, _ *FakeClock, timer *Timer) {
+				_ = timer.Run(ctx, 90*time.Minute, func() {})
+			}},
+		{name: "Schedulerでロガーのハンドラからタイマーを操作しても止まらない",
+			run: func(ctx context.Context, clock *FakeClock, timer *Timer) {
+				scheduler := NewScheduler().SetClock(clock)
+				_ = scheduler.Add("job", timer, 90*time.Minute, func(context.Context) error { return nil })
+				_ = scheduler.Run(ctx)
+			}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			clock := NewFakeClock(time.Date(2021, 1, 4, 8, 0, 0, 0, time.Local))
+			timer := new(Timer)
+			timer.SetClock(clock).AddTerm(NewTerm(NewTime(9, 0, 0), NewTime(10, 0, 0))).
+				SetLogger(slog.New(callbackHandler{callback: func() { timer.IsPaused() }}))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go test.run(ctx, clock, timer)
+
+			// 9:00の次の10:30は期間外なので、次の開始日時に決め直したことを記録する
+			clock.BlockUntil(1)
+			clock.Set(time.Date(2021, 1, 4, 9, 0, 0, 0, time.Local))
+			waited := make(chan struct{})
+			go func() {
+				defer close(waited)
+				clock.BlockUntil(1)
+			}()
+			select {
+			case <-waited:
+			case <-time.After(time.Second):
+				t.Fatalf("%s error\nlogger is blocked\n", t.Name())
+			}
+			want := time.Date(2021, 1, 5, 9, 0, 0, 0, time.Local)
+			if got := timer.NextRun(); !reflect.DeepEqual(want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+			}
+		})
+	}
+}
+
+func Test_Timer_nextStart_Log(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	timer := &Timer{terms: []Term{NewTerm(NewTime(9, 0, 0), NewTime(10, 0, 0))}, calendar: alwaysHoliday{}, logger: testLogger(&buf)}
+	_, entries := timer.nextStart(time.Date(2021, 1, 4, 10, 0, 0, 0, time.UTC))
+	timer.logEntries(entries)
+	want := `level=WARN msg="no term starts within a year, falling back to the next midnight" from=2021-01-04T10:00:00.000Z` + "\n"
+	got := buf.String()
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), want, got)
+	}
+}
+
+func Test_Timer_execute_Log(t *testing.T) {
+	t.Parallel()
+	taskErr := errors.New("task error")
+	tests := []struct {
+		name string
+		task func(ctx context.Context) error
+		want string
+	}{
+		{name: "成功したらDebug", task: func(context.Context) error { return nil },
+			want: `level=DEBUG msg="task finished" scheduled=2021-01-04T09:00:00.000Z duration=0s` + "\n"},
+		{name: "失敗したらWarn", task: func(context.Context) error { return taskErr },
+			want: `level=WARN msg="task failed" scheduled=2021-01-04T09:00:00.000Z duration=0s error="task error"` + "\n"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			clock := NewFakeClock(time.Date(2021, 1, 4, 9, 0, 0, 0, time.UTC))
+			timer := &Timer{clock: clock, taskRunning: 1, logger: testLogger(&buf)}
+			timer.execute(context.Background(), clock.Now(), test.task)
+			got := buf.String()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Timer_nextStart_Precise(t *testing.T) {
+	t.Parallel()
+	timer := &Timer{terms: []Term{NewPreciseTerm(NewPreciseTime(8, 59, 59, 500_000_000), NewPreciseTime(9, 0, 0, 250_000_000))}}
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{name: "開始前なら当日の開始時刻", now: time.Date(2021, 1, 4, 8, 59, 59, 0, time.Local),
+			want: time.Date(2021, 1, 4, 8, 59, 59, 500_000_000, time.Local)},
+		{name: "開始後なら翌日の開始時刻", now: time.Date(2021, 1, 4, 8, 59, 59, 500_000_000, time.Local),
+			want: time.Date(2021, 1, 5, 8, 59, 59, 500_000_000, time.Local)},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got, _ := timer.nextStart(test.now)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
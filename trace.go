@@ -0,0 +1,65 @@
+package gotimer
+
+import (
+	"context"
+	"time"
+)
+
+// Tracer - タスクの実行ごとにSpanを作るもの
+//   OpenTelemetryなどのトレーサーに合わせて実装する
+type Tracer interface {
+	// Start - ctxを親にしたSpanを始め、Spanを持たせたctxと一緒に返す
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span - 1回のタスクの実行を表すSpan
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Attribute - Spanに付ける属性
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+const (
+	AttributeJob       = "gotimer.job"        // Timerの名前
+	AttributeScheduled = "gotimer.scheduled"  // 実行予定日時
+	AttributeTermIndex = "gotimer.term_index" // 実行予定日時を含む期間の位置 なければ-1
+	AttributeOutcome   = "gotimer.outcome"    // 実行結果
+)
+
+// defaultSpanName - Timerに名前がないときのSpanの名前
+const defaultSpanName = "gotimer.task"
+
+// startSpan - トレーサーが設定されていれば、実行のSpanを始める
+func (t *Timer) startSpan(ctx context.Context, scheduled time.Time) (context.Context, Span) {
+	if t.tracer == nil {
+		return ctx, nil
+	}
+
+	name := t.Name()
+	spanName := name
+	if spanName == "" {
+		spanName = defaultSpanName
+	}
+	return t.tracer.Start(ctx, spanName,
+		Attribute{Key: AttributeJob, Value: name},
+		Attribute{Key: AttributeScheduled, Value: scheduled},
+		Attribute{Key: AttributeTermIndex, Value: t.termIndex(scheduled)})
+}
+
+// endSpan - 実行の記録から実行結果をSpanに残して終える
+func endSpan(span Span, e Execution) {
+	if span == nil {
+		return
+	}
+	span.SetAttributes(Attribute{Key: AttributeOutcome, Value: e.Outcome.String()})
+	if e.Err != nil {
+		span.RecordError(e.Err)
+	}
+	span.End()
+}
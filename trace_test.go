@@ -0,0 +1,103 @@
+package gotimer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testSpanKey struct{}
+
+type testTracer struct {
+	spans []*testSpan
+	mtx   sync.Mutex
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	span := &testSpan{name: name, attrs: attrs}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+type testSpan struct {
+	name  string
+	attrs []Attribute
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttributes(attrs ...Attribute) { s.attrs = append(s.attrs, attrs...) }
+func (s *testSpan) RecordError(err error)            { s.err = err }
+func (s *testSpan) End()                             { s.ended = true }
+
+func Test_Timer_SetName(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		timerRunning bool
+		want         string
+	}{
+		{name: "timerRunningでなければ設定が反映される", timerRunning: false, want: "job"},
+		{name: "timerRunningであれば設定が反映されない", timerRunning: true, want: ""},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			timer := &Timer{timerRunning: test.timerRunning}
+			timer.SetName("job")
+			got := timer.Name()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v\n", t.Name(), test.want, got)
+			}
+		})
+	}
+}
+
+func Test_Timer_execute_Span(t *testing.T) {
+	t.Parallel()
+	taskErr := errors.New("task error")
+	scheduled := time.Date(2021, 1, 4, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		name  string
+		timer *Timer
+		err   error
+		want  testSpan
+	}{
+		{name: "名前がなければ既定の名前で、実行結果を残す",
+			timer: &Timer{},
+			want: testSpan{name: "gotimer.task", ended: true, attrs: []Attribute{
+				{Key: AttributeJob, Value: ""}, {Key: AttributeScheduled, Value: scheduled}, {Key: AttributeTermIndex, Value: -1},
+				{Key: AttributeOutcome, Value: "success"}}}},
+		{name: "名前と期間の位置を付けて、エラーを残す",
+			timer: &Timer{name: "job", terms: []Term{NewTerm(NewTime(9, 0, 0), NewTime(10, 0, 0)), NewTerm(NewTime(11, 0, 0), NewTime(13, 0, 0))}},
+			err:   taskErr,
+			want: testSpan{name: "job", ended: true, err: taskErr, attrs: []Attribute{
+				{Key: AttributeJob, Value: "job"}, {Key: AttributeScheduled, Value: scheduled}, {Key: AttributeTermIndex, Value: 1},
+				{Key: AttributeOutcome, Value: "error"}}}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			tracer := &testTracer{}
+			test.timer.SetTracer(tracer)
+			test.timer.taskRunning = 1
+			var inTask interface{}
+			test.timer.execute(context.Background(), scheduled, func(ctx context.Context) error {
+				inTask = ctx.Value(testSpanKey{})
+				return test.err
+			})
+			if len(tracer.spans) != 1 || inTask != tracer.spans[0] || !reflect.DeepEqual(test.want, *tracer.spans[0]) {
+				t.Errorf("%s error\nwant: %+v\ngot: %+v, %+v\n", t.Name(), test.want, tracer.spans, inTask)
+			}
+		})
+	}
+}